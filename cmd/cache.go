@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ashishra0/issue-finder/internal/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the HTTP cache",
+	Long: `Manage the on-disk cache of GitHub API responses.
+
+Cached responses are revalidated with conditional requests, which GitHub
+does not count against your rate limit.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached responses",
+	Long:  `Remove all cached GitHub API responses. The next search downloads everything again.`,
+	Example: `  # Clear the cache
+  issue-finder cache clear`,
+	RunE: runCacheClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	cacheDir := getCacheDir()
+
+	removed, err := github.ClearCache(cacheDir)
+	if err != nil {
+		return fmt.Errorf("error clearing cache: %w", err)
+	}
+
+	fmt.Printf("Removed %d cached responses from: %s\n", removed, cacheDir)
+
+	return nil
+}
+
+func getCacheDir() string {
+	cacheDir := viper.GetString("cache.dir")
+	if cacheDir != "" {
+		return expandPath(cacheDir)
+	}
+
+	return github.DefaultCacheDir()
+}
+
+// cacheEnabled defaults to true unless explicitly disabled in config
+func cacheEnabled() bool {
+	if !viper.IsSet("cache.enabled") {
+		return true
+	}
+	return viper.GetBool("cache.enabled")
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/ashishra0/issue-finder/internal/ai"
+	"github.com/ashishra0/issue-finder/internal/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -84,6 +87,9 @@ preferences:
   # Where to store state
   state_path: "~/.issue-finder-state.json"
 
+  # Extra skill-to-search-qualifier mappings (see: issue-finder skills --help)
+  skills_file: "~/.issue-finder-skills.yaml"
+
   # Enable/disable notifications
   notify_on_completion: true
 
@@ -94,6 +100,168 @@ api:
   # Environment variable names for API keys
   anthropic_key_env: "ANTHROPIC_API_KEY"
   github_token_env: "GITHUB_TOKEN"
+
+  # GitHub API root; change for GitHub Enterprise Server
+  # (e.g. https://github.example.com/api/v3)
+  github_base_url: "https://api.github.com"
+
+  # Search backend: "rest", or "graphql" to fetch issues, repository
+  # details, recent comments and linked pull requests in one query
+  github_backend: "rest"
+
+  # Retries for rate limited requests, and the initial backoff for
+  # secondary rate limits (doubled on each attempt)
+  github_max_retries: 4
+  github_retry_backoff: "60s"
+
+  # Rotate between several GitHub tokens when one runs out of quota. Tokens
+  # are read from these environment variables (default: github_token_env)
+  # and from a file with one token per line
+  # github_token_envs: ["GITHUB_TOKEN", "GITHUB_TOKEN_2"]
+  # github_tokens_file: "~/.issue-finder-tokens"
+
+  # Authenticate as a GitHub App installation instead of with a personal
+  # token (the app's private key is read from a file or an env variable)
+  # github_app:
+  #   app_id: "123456"
+  #   installation_id: 7890123
+  #   private_key_path: "~/.issue-finder-app.pem"
+  #   # private_key_env: "GITHUB_APP_PRIVATE_KEY"
+
+ai:
+  # LLM provider used to evaluate issues: "anthropic", "openai" for any
+  # OpenAI-compatible chat completions API, or "ollama" to evaluate
+  # offline with a local model
+  provider: "anthropic"
+
+  # Model, response length and sampling; empty values use the provider's
+  # defaults (claude-sonnet-4-5-20250929 for Anthropic, 4096 tokens)
+  model: ""
+  max_tokens: 4096
+  # temperature: 0.2
+
+  # Give up on an evaluation request after this long
+  # timeout: "5m"
+
+  # Number of best matches to keep from each search
+  max_matches: 5
+
+  # Large candidate sets are split into batches of about this many prompt
+  # tokens (default 20000, or 3000 for ollama); the best issues of every
+  # batch are then ranked against each other. Batches can be evaluated in
+  # parallel.
+  # batch_tokens: 20000
+  batch_concurrency: 1
+
+  # openai:
+  #   base_url: "https://api.openai.com/v1"
+  #   model: "gpt-4o-mini"
+  #   api_key_env: "OPENAI_API_KEY"
+
+  # Local Ollama server; set api to "openai" for llama.cpp's server
+  # ollama:
+  #   base_url: "http://localhost:11434"
+  #   model: "llama3.1:8b"
+  #   api: "ollama"
+
+search:
+  # Only issues created within this window (d, w, m or y, e.g. 90d, 6m)
+  created_within: "180d"
+
+  # Only issues updated within this window (empty for no limit)
+  updated_within: ""
+
+  # One query per skill per label; an empty list searches without labels
+  labels:
+    - good first issue
+    - help wanted
+
+  # Comment and reaction bounds (0 disables)
+  min_comments: 1
+  max_comments: 0
+  min_reactions: 0
+
+  # Result order: created, updated, comments, reactions, interactions, or
+  # mixed to run every query once per ordering in mixed_sorts and
+  # interleave the results (costs one query per ordering)
+  sort: "created"
+  mixed_sorts: ["created", "comments", "reactions"]
+
+  # Per-skill overrides of the settings above
+  # skills:
+  #   python:
+  #     created_within: "30d"
+  #     labels: ["good first issue"]
+  #     max_comments: 10
+  #     sort: "mixed"
+
+  # Pages of search results to fetch per query (100 results per page)
+  max_pages: 3
+
+  # Stop paginating a query after this many results (GitHub caps at 1000)
+  max_results_per_query: 300
+
+  # Queries to run at the same time (they share GitHub's rate limits)
+  concurrency: 3
+
+  # Characters of each issue body sent for evaluation, after template
+  # comments and checklists are stripped
+  body_max_chars: 800
+
+# Scan the open issues of these GitHub repositories instead of searching
+# (same as passing --repo to search)
+# watch_repos:
+#   - golang/go
+#   - spf13/cobra
+
+sources:
+  github:
+    enabled: true
+
+  # GitLab instances to search as well (token_env defaults to GITLAB_TOKEN)
+  # gitlab:
+  #   - name: gitlab.com
+  #     base_url: "https://gitlab.com"
+  #     token_env: "GITLAB_TOKEN"
+  #     projects_per_query: 20
+
+  # Gitea or Forgejo instances, such as Codeberg (token_env defaults to GITEA_TOKEN)
+  # gitea:
+  #   - name: codeberg.org
+  #     base_url: "https://codeberg.org"
+  #     token_env: "GITEA_TOKEN"
+  #     projects_per_query: 20
+
+cache:
+  # Cache GitHub responses on disk and revalidate them with ETags
+  enabled: true
+
+  # Cache location (default: your user cache directory)
+  # dir: "~/.cache/issue-finder/http"
+
+  # Discard cached responses older than this
+  ttl: "24h"
+
+filters:
+  # Skip repositories with fewer stars than this (0 disables)
+  min_stars: 10
+
+  # Skip repositories without a push in this many days (0 disables)
+  max_inactive_days: 365
+
+  # Keep issues from archived repositories
+  include_archived: false
+
+  # Issues someone already claimed in a comment or with an open pull
+  # request: "drop", "flag" (let the AI decide) or "keep"
+  claimed: "drop"
+
+  # Repository and organization allow/deny lists; globs like "my-org/*"
+  # are supported. Include lists restrict the search to matching repos.
+  include_repos: []
+  exclude_repos: []
+  include_orgs: []
+  exclude_orgs: []
 `
 
 	err := os.WriteFile(configPath, []byte(defaultConfig), 0644)
@@ -165,6 +333,63 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	fmt.Println("API:")
 	fmt.Printf("  Anthropic key env: %s\n", getEnvVarName("api.anthropic_key_env", "ANTHROPIC_API_KEY"))
 	fmt.Printf("  GitHub token env: %s\n", getEnvVarName("api.github_token_env", "GITHUB_TOKEN"))
+	if envs := viper.GetStringSlice("api.github_token_envs"); len(envs) > 0 {
+		fmt.Printf("  GitHub token pool envs: %s\n", strings.Join(envs, ", "))
+	}
+	if tokensFile := viper.GetString("api.github_tokens_file"); tokensFile != "" {
+		fmt.Printf("  GitHub tokens file: %s\n", tokensFile)
+	}
+	if appID := viper.GetString("api.github_app.app_id"); appID != "" {
+		fmt.Printf("  GitHub App: %s (installation %d)\n", appID, viper.GetInt64("api.github_app.installation_id"))
+	}
+	fmt.Printf("  GitHub base URL: %s\n", getEnvVarName("api.github_base_url", github.DefaultBaseURL))
+	fmt.Printf("  GitHub backend: %s\n", getEnvVarName("api.github_backend", github.BackendREST))
+
+	fmt.Println()
+	fmt.Println("AI:")
+	provider := getEnvVarName("ai.provider", ai.ProviderAnthropic)
+	fmt.Printf("  Provider: %s\n", provider)
+	switch provider {
+	case ai.ProviderOpenAI:
+		fmt.Printf("  Base URL: %s\n", getEnvVarName("ai.openai.base_url", ai.DefaultOpenAIBaseURL))
+		fmt.Printf("  Key env: %s\n", getEnvVarName("ai.openai.api_key_env", "OPENAI_API_KEY"))
+	case ai.ProviderOllama:
+		fmt.Printf("  Base URL: %s\n", getEnvVarName("ai.ollama.base_url", ai.DefaultOllamaBaseURL))
+		fmt.Printf("  API: %s\n", getEnvVarName("ai.ollama.api", ai.LocalAPIOllama))
+	}
+
+	model := configuredModel(provider)
+	if model == "" {
+		model = ai.DefaultModel(provider)
+	}
+	fmt.Printf("  Model: %s\n", model)
+
+	maxTokens := viper.GetInt("ai.max_tokens")
+	if maxTokens == 0 {
+		maxTokens = ai.DefaultMaxTokens
+	}
+	fmt.Printf("  Max tokens: %d\n", maxTokens)
+	if viper.IsSet("ai.temperature") {
+		fmt.Printf("  Temperature: %g\n", viper.GetFloat64("ai.temperature"))
+	}
+	if timeout := viper.GetString("ai.timeout"); timeout != "" {
+		fmt.Printf("  Timeout: %s\n", timeout)
+	}
+
+	maxAIMatches := viper.GetInt("ai.max_matches")
+	if maxAIMatches == 0 {
+		maxAIMatches = ai.DefaultMaxMatches
+	}
+	fmt.Printf("  Max matches: %d\n", maxAIMatches)
+
+	batchTokens := viper.GetInt("ai.batch_tokens")
+	if batchTokens == 0 {
+		batchTokens = ai.DefaultBatchTokens
+		if provider == ai.ProviderOllama {
+			batchTokens = ai.DefaultLocalBatchTokens
+		}
+	}
+	fmt.Printf("  Batch size: %d tokens (%d at a time)\n", batchTokens, max(viper.GetInt("ai.batch_concurrency"), 1))
 
 	anthropicKey := os.Getenv(getEnvVarName("api.anthropic_key_env", "ANTHROPIC_API_KEY"))
 	githubToken := os.Getenv(getEnvVarName("api.github_token_env", "GITHUB_TOKEN"))
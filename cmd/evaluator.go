@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/ai"
+	"github.com/spf13/viper"
+)
+
+// buildEvaluator creates the evaluator for the provider set in ai.provider
+// (default anthropic), checking its settings and that its API key is
+// available
+func buildEvaluator() (ai.Evaluator, error) {
+	provider := viper.GetString("ai.provider")
+	if provider == "" {
+		provider = ai.ProviderAnthropic
+	}
+
+	params, err := loadAIParams(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluator ai.Evaluator
+	batchTokens := viper.GetInt("ai.batch_tokens")
+
+	switch provider {
+	case ai.ProviderAnthropic:
+		keyEnv := getEnvVarName("api.anthropic_key_env", "ANTHROPIC_API_KEY")
+		apiKey := os.Getenv(keyEnv)
+		if apiKey == "" {
+			return nil, fmt.Errorf("%s environment variable not set", keyEnv)
+		}
+		evaluator = ai.NewAnthropicEvaluator(apiKey, params)
+
+	case ai.ProviderOpenAI:
+		keyEnv := getEnvVarName("ai.openai.api_key_env", "OPENAI_API_KEY")
+		apiKey := os.Getenv(keyEnv)
+		if apiKey == "" {
+			return nil, fmt.Errorf("%s environment variable not set", keyEnv)
+		}
+		evaluator = ai.NewOpenAIEvaluator(viper.GetString("ai.openai.base_url"), apiKey, params)
+
+	case ai.ProviderOllama:
+		// Local models need no API key
+		api := viper.GetString("ai.ollama.api")
+		if api != "" && api != ai.LocalAPIOllama && api != ai.LocalAPIOpenAI {
+			return nil, fmt.Errorf("unknown ai.ollama.api %q (expected %q or %q)", api, ai.LocalAPIOllama, ai.LocalAPIOpenAI)
+		}
+		evaluator = ai.NewOllamaEvaluator(viper.GetString("ai.ollama.base_url"), api, params)
+		if batchTokens == 0 {
+			batchTokens = ai.DefaultLocalBatchTokens
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown ai.provider %q (expected %q, %q or %q)", provider, ai.ProviderAnthropic, ai.ProviderOpenAI, ai.ProviderOllama)
+	}
+
+	return ai.NewChunkedEvaluator(evaluator, ai.ChunkConfig{
+		BatchTokens: batchTokens,
+		Concurrency: viper.GetInt("ai.batch_concurrency"),
+		MaxMatches:  params.MaxMatches,
+	}), nil
+}
+
+// loadAIParams reads ai.model, ai.max_tokens, ai.temperature, ai.timeout
+// and ai.max_matches and validates them for provider, along with the batch
+// settings
+func loadAIParams(provider string) (ai.Params, error) {
+	params := ai.Params{
+		Model:      configuredModel(provider),
+		MaxTokens:  viper.GetInt("ai.max_tokens"),
+		MaxMatches: viper.GetInt("ai.max_matches"),
+	}
+	if params.MaxMatches == 0 {
+		params.MaxMatches = ai.DefaultMaxMatches
+	}
+
+	if viper.IsSet("ai.temperature") {
+		temperature := viper.GetFloat64("ai.temperature")
+		params.Temperature = &temperature
+	}
+
+	if timeout := viper.GetString("ai.timeout"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return params, fmt.Errorf("invalid ai.timeout %q: %w", timeout, err)
+		}
+		params.Timeout = d
+	}
+
+	if err := ai.ValidateParams(provider, params); err != nil {
+		return params, fmt.Errorf("invalid ai config: %w", err)
+	}
+	if viper.GetInt("ai.batch_tokens") < 0 || viper.GetInt("ai.batch_concurrency") < 0 {
+		return params, fmt.Errorf("invalid ai config: ai.batch_tokens and ai.batch_concurrency must not be negative")
+	}
+
+	return params, nil
+}
+
+// configuredModel returns ai.model, falling back to the model set in the
+// provider's own section, or "" for the provider's default
+func configuredModel(provider string) string {
+	if model := viper.GetString("ai.model"); model != "" {
+		return model
+	}
+
+	switch provider {
+	case ai.ProviderOpenAI:
+		return viper.GetString("ai.openai.model")
+	case ai.ProviderOllama:
+		return viper.GetString("ai.ollama.model")
+	default:
+		return ""
+	}
+}
+
+// providerName describes the configured evaluator in progress output
+func providerName() string {
+	switch viper.GetString("ai.provider") {
+	case ai.ProviderOpenAI:
+		return "the OpenAI-compatible API"
+	case ai.ProviderOllama:
+		return "the local model"
+	default:
+		return "Anthropic AI"
+	}
+}
@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ashishra0/issue-finder/internal/ai"
 	"github.com/ashishra0/issue-finder/internal/github"
 	"github.com/ashishra0/issue-finder/internal/output"
+	"github.com/ashishra0/issue-finder/internal/source"
 	"github.com/ashishra0/issue-finder/internal/state"
 	"github.com/ashishra0/issue-finder/pkg/types"
 	"github.com/spf13/cobra"
@@ -17,24 +20,30 @@ import (
 )
 
 var (
-	skills     []string
-	interests  []string
-	experience int
-	outputPath string
-	statePath  string
-	noNotify   bool
+	skills      []string
+	interests   []string
+	experience  int
+	outputPath  string
+	statePath   string
+	noNotify    bool
+	watchRepos  []string
+	model       string
+	maxTokens   int
+	temperature float64
+	aiTimeout   string
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search",
 	Short: "Search for OSS contribution opportunities",
-	Long: `Search GitHub for open source contribution opportunities that match
-your skills, interests, and experience level.
+	Long: `Search GitHub (and any configured GitLab or Gitea instances) for open source
+contribution opportunities that match your skills, interests, and
+experience level.
 
 The command will:
-1. Search GitHub for relevant issues
+1. Search for relevant issues
 2. Filter out already processed issues
-3. Send new issues to Anthropic AI for evaluation
+3. Send new issues to the configured AI provider for evaluation
 4. Write results to a markdown file
 
 You can provide your profile via command-line flags or a config file.`,
@@ -45,7 +54,13 @@ You can provide your profile via command-line flags or a config file.`,
   issue-finder search --config ~/.my-profile.yaml
 
   # Specify custom output location
-  issue-finder search --output ~/my-contributions.md`,
+  issue-finder search --output ~/my-contributions.md
+
+  # Only look at issues in repositories you already follow
+  issue-finder search --repo golang/go --repo spf13/cobra
+
+  # Try a different model with room for a longer answer
+  issue-finder search --model claude-haiku-4-5 --max-tokens 8192`,
 	RunE: runSearch,
 }
 
@@ -58,12 +73,22 @@ func init() {
 	searchCmd.Flags().StringVar(&outputPath, "output", "", "Output file path (default: ~/contributions.md)")
 	searchCmd.Flags().StringVar(&statePath, "state", "", "State file path (default: ~/.issue-finder-state.json)")
 	searchCmd.Flags().BoolVar(&noNotify, "no-notify", false, "Disable desktop notifications")
+	searchCmd.Flags().StringSliceVar(&watchRepos, "repo", []string{}, "Scan the open issues of this GitHub repository (owner/name) instead of searching; repeatable")
+	searchCmd.Flags().StringVar(&model, "model", "", "Model used to evaluate issues (default depends on the AI provider)")
+	searchCmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Maximum tokens in the model's response (default 4096)")
+	searchCmd.Flags().Float64Var(&temperature, "temperature", 0, "Sampling temperature (default depends on the AI provider)")
+	searchCmd.Flags().StringVar(&aiTimeout, "ai-timeout", "", "Timeout for the evaluation request, e.g. 10m")
 
 	viper.BindPFlag("profile.skills", searchCmd.Flags().Lookup("skills"))
 	viper.BindPFlag("profile.interests", searchCmd.Flags().Lookup("interests"))
 	viper.BindPFlag("profile.experience_years", searchCmd.Flags().Lookup("experience"))
 	viper.BindPFlag("preferences.output_path", searchCmd.Flags().Lookup("output"))
 	viper.BindPFlag("preferences.state_path", searchCmd.Flags().Lookup("state"))
+	viper.BindPFlag("watch_repos", searchCmd.Flags().Lookup("repo"))
+	viper.BindPFlag("ai.model", searchCmd.Flags().Lookup("model"))
+	viper.BindPFlag("ai.max_tokens", searchCmd.Flags().Lookup("max-tokens"))
+	viper.BindPFlag("ai.temperature", searchCmd.Flags().Lookup("temperature"))
+	viper.BindPFlag("ai.timeout", searchCmd.Flags().Lookup("ai-timeout"))
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
@@ -87,14 +112,14 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 	stateFile = expandPath(stateFile)
 
-	githubToken := os.Getenv("GITHUB_TOKEN")
-	if githubToken == "" {
-		return fmt.Errorf("GITHUB_TOKEN environment variable not set")
+	evaluator, err := buildEvaluator()
+	if err != nil {
+		return err
 	}
 
-	anthropicKey := os.Getenv("ANTHROPIC_API_KEY")
-	if anthropicKey == "" {
-		return fmt.Errorf("ANTHROPIC_API_KEY environment variable not set")
+	sources, err := buildSources()
+	if err != nil {
+		return err
 	}
 
 	progress := output.NewProgressFormatter(quiet)
@@ -103,12 +128,53 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	stateMgr := state.NewManager(stateFile)
 	currentState := stateMgr.Load()
 
-	progress.Step(1, "Searching GitHub for relevant issues...")
+	progress.Step(1, "Searching for relevant issues...")
 
-	ghClient := github.NewClient(githubToken)
-	recentIssues := ghClient.FetchRelevantIssues(profile)
+	// Errors past this point are runtime failures, not usage mistakes
+	cmd.SilenceUsage = true
+
+	ctx := cmd.Context()
+
+	recentIssues := []types.CandidateIssue{}
+	var fetchErrs []error
+	var failedRequests int
+
+	// Watched repositories bypass the allow and deny lists
+	filters := loadFilters()
+	watching := len(viper.GetStringSlice("watch_repos")) > 0
+
+	for _, src := range sources {
+		fetchResult, fetchErr := src.FetchRelevantIssues(ctx, profile)
+		if ctx.Err() != nil {
+			return fmt.Errorf("search cancelled: %w", ctx.Err())
+		}
+		if !watching {
+			fetchResult.ExcludeRepos(filters)
+		}
+
+		progress.Detail(fmt.Sprintf("%s: found %d issues across %d queries", src.Name(), len(fetchResult.Issues), fetchResult.Queries))
+		if fetchResult.Excluded > 0 {
+			progress.Detail(fmt.Sprintf("Excluded %d issues by repository and organization filters", fetchResult.Excluded))
+		}
+		if len(fetchResult.Incomplete) > 0 {
+			progress.Warning(fmt.Sprintf("%s reported incomplete results for %d queries", src.Name(), len(fetchResult.Incomplete)))
+		}
+
+		failed, err := reportFetchErrors(progress, fetchErr)
+		if err != nil {
+			return fmt.Errorf("%s search failed: %w", src.Name(), err)
+		}
+		if fetchErr != nil {
+			failedRequests += failed
+			fetchErrs = append(fetchErrs, fetchErr)
+		}
+
+		recentIssues = append(recentIssues, fetchResult.Issues...)
+	}
 
-	progress.Detail(fmt.Sprintf("Found %d issues across multiple queries", len(recentIssues)))
+	if failedRequests > 0 && len(recentIssues) == 0 {
+		return fmt.Errorf("search failed, no results were retrieved: %w", errors.Join(fetchErrs...))
+	}
 	progress.EmptyLine()
 
 	progress.Step(2, "Filtering processed issues...")
@@ -117,16 +183,77 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 	progress.Detail(fmt.Sprintf("%d already evaluated, %d new issues to process",
 		len(recentIssues)-len(newIssues), len(newIssues)))
+
+	// Repository lookups and claim detection cost requests, so only check
+	// new issues
+	newIssues, unhealthyCount, healthErr := filterRepositories(ctx, sources, newIssues)
+	if ctx.Err() != nil {
+		return fmt.Errorf("search cancelled: %w", ctx.Err())
+	}
+	if unhealthyCount > 0 {
+		progress.Detail(fmt.Sprintf("Dropped %d issues from archived, inactive or small repositories", unhealthyCount))
+	}
+
+	failedRepoLookups, err := reportFetchErrors(progress, healthErr)
+	if err != nil {
+		return fmt.Errorf("repository lookup failed: %w", err)
+	}
+	if healthErr != nil {
+		failedRequests += failedRepoLookups
+		fetchErrs = append(fetchErrs, healthErr)
+	}
+
+	newIssues, claimedCount, claimErr := detectClaims(ctx, sources, newIssues)
+	if ctx.Err() != nil {
+		return fmt.Errorf("search cancelled: %w", ctx.Err())
+	}
+	if claimedCount > 0 {
+		progress.Detail(fmt.Sprintf("%d issues already claimed by someone else", claimedCount))
+	}
+
+	failedClaimChecks, err := reportFetchErrors(progress, claimErr)
+	if err != nil {
+		return fmt.Errorf("claim detection failed: %w", err)
+	}
+	if claimErr != nil {
+		failedRequests += failedClaimChecks
+		fetchErrs = append(fetchErrs, claimErr)
+	}
+
+	// Issues dropped as claimed or unhealthy stay unprocessed so they come
+	// back once the claim lapses or the repository recovers
+	stateMgr.MarkProcessed(&currentState, newIssues)
+
+	for _, src := range sources {
+		if ghClient, ok := src.(*github.Client); ok && ghClient.TokenCount() > 1 {
+			reportTokenUsage(progress, ghClient.TokenUsage())
+		}
+	}
 	progress.EmptyLine()
 
 	var newMatchesCount int
 
 	if len(newIssues) > 0 {
 		progress.Step(3, "Evaluating with AI...")
-		progress.Detail(fmt.Sprintf("Sending %d issues to Anthropic AI for evaluation...", len(newIssues)))
+		progress.Detail(fmt.Sprintf("Sending %d issues to %s for evaluation...", len(newIssues), providerName()))
+		if chunked, ok := evaluator.(*ai.ChunkedEvaluator); ok {
+			if batches := chunked.BatchCount(newIssues); batches > 1 {
+				progress.Detail(fmt.Sprintf("Split into %d batches, then ranking the best of each", batches))
+			}
+		}
 
-		evaluator := ai.NewEvaluator(anthropicKey)
-		matches := evaluator.EvaluateIssues(profile, newIssues)
+		matches, err := evaluator.EvaluateIssues(ctx, profile, newIssues)
+		if ctx.Err() != nil {
+			return fmt.Errorf("search cancelled: %w", ctx.Err())
+		}
+		if err != nil {
+			var parseErr *ai.ParseError
+			if errors.As(err, &parseErr) {
+				log.Printf("Response text: %s", parseErr.Response)
+			}
+			// Leave the state untouched so these issues are evaluated next run
+			return fmt.Errorf("evaluation failed: %w", err)
+		}
 
 		newMatchesCount = len(matches)
 
@@ -140,7 +267,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 		stateMgr.AddMatches(&currentState, matches, maxMatches)
 
-		log.Printf("Claude found %d good matches", len(matches))
+		log.Printf("Evaluator found %d good matches", len(matches))
 	} else {
 		progress.Step(3, "Evaluating with AI...")
 		progress.Detail("No new issues to evaluate")
@@ -149,7 +276,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 	progress.Step(4, "Writing results...")
 
-	err := output.WriteMarkdownFile(outputFile, currentState)
+	err = output.WriteMarkdownFile(outputFile, currentState)
 	if err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
@@ -193,6 +320,11 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if failedRequests > 0 {
+		return fmt.Errorf("%d requests failed, results are partial: %w",
+			failedRequests, errors.Join(fetchErrs...))
+	}
+
 	return nil
 }
 
@@ -205,6 +337,96 @@ func loadProfile() types.UserProfile {
 	}
 }
 
+// reportFetchErrors prints a warning per failed request and returns how many
+// failed. err may be a *source.FetchError or several joined with
+// errors.Join; other errors are returned as is.
+func reportFetchErrors(progress *output.ProgressFormatter, err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+
+	errs := []error{err}
+	if _, ok := err.(*source.FetchError); !ok {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+	}
+
+	failed := 0
+	for _, inner := range errs {
+		fetchErr, ok := inner.(*source.FetchError)
+		if !ok {
+			return 0, err
+		}
+
+		for _, queryErr := range fetchErr.Errors {
+			progress.Warning(fmt.Sprintf("%s: %v", fetchErr.Source, queryErr))
+			failed++
+		}
+	}
+
+	return failed, nil
+}
+
+// reportTokenUsage prints the requests made with each pooled GitHub token
+// and the quota it has left
+func reportTokenUsage(progress *output.ProgressFormatter, usage []github.TokenUsage) {
+	for _, u := range usage {
+		resources := make([]string, 0, len(u.Remaining))
+		for resource := range u.Remaining {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+
+		quota := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			quota = append(quota, fmt.Sprintf("%s %d left", resource, u.Remaining[resource]))
+		}
+
+		line := fmt.Sprintf("Token %s: %d requests, %d rate limited", u.Name, u.Requests, u.RateLimited)
+		if len(quota) > 0 {
+			line += " (" + strings.Join(quota, ", ") + ")"
+		}
+		progress.Detail(line)
+	}
+}
+
+func loadSearchConfig() (types.SearchConfig, error) {
+	searchConfig := github.DefaultSearchConfig()
+
+	if err := viper.UnmarshalKey("search", &searchConfig); err != nil {
+		return searchConfig, fmt.Errorf("invalid search config: %w", err)
+	}
+
+	if err := github.ValidateSearchConfig(searchConfig); err != nil {
+		return searchConfig, fmt.Errorf("invalid search config: %w", err)
+	}
+
+	return searchConfig, nil
+}
+
+func loadFilters() types.FiltersConfig {
+	filters := types.FiltersConfig{
+		MinStars:        viper.GetInt("filters.min_stars"),
+		MaxInactiveDays: viper.GetInt("filters.max_inactive_days"),
+		IncludeArchived: viper.GetBool("filters.include_archived"),
+		Claimed:         viper.GetString("filters.claimed"),
+		IncludeRepos:    viper.GetStringSlice("filters.include_repos"),
+		ExcludeRepos:    viper.GetStringSlice("filters.exclude_repos"),
+		IncludeOrgs:     viper.GetStringSlice("filters.include_orgs"),
+		ExcludeOrgs:     viper.GetStringSlice("filters.exclude_orgs"),
+	}
+
+	if !viper.IsSet("filters.min_stars") {
+		filters.MinStars = 10
+	}
+	if !viper.IsSet("filters.max_inactive_days") {
+		filters.MaxInactiveDays = 365
+	}
+
+	return filters
+}
+
 func validateProfile(profile types.UserProfile) error {
 	if len(profile.Skills) == 0 {
 		return fmt.Errorf("no skills specified\n  Use --skills flag or set profile.skills in config file\n  Example: contribution-finder search --skills \"Go,Python\"")
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ashishra0/issue-finder/internal/skillmap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var showAllSkills bool
+
+var skillsCmd = &cobra.Command{
+	Use:   "skills [skill...]",
+	Short: "Show how skills translate into GitHub search qualifiers",
+	Long: `Show the GitHub search qualifiers used for each skill.
+
+Without arguments the skills from your profile are shown. Built-in
+mappings can be extended or overridden in a skills file
+(default: ~/.issue-finder-skills.yaml):
+
+  skills:
+    - name: Next.js
+      aliases: [nextjs]
+      qualifiers: ["topic:nextjs", "topic:react"]
+
+Each qualifier produces its own search query. Skills without a mapping
+fall back to a topic qualifier derived from the name.`,
+	Example: `  # Show the qualifiers for your profile skills
+  issue-finder skills
+
+  # Check specific skills
+  issue-finder skills "machine learning" Next.js
+
+  # List every known mapping
+  issue-finder skills --all`,
+	RunE: runSkills,
+}
+
+func init() {
+	rootCmd.AddCommand(skillsCmd)
+
+	skillsCmd.Flags().BoolVar(&showAllSkills, "all", false, "List every known skill mapping")
+}
+
+func runSkills(cmd *cobra.Command, args []string) error {
+	skillsFile := getSkillsFilePath()
+
+	skillMap, err := skillmap.Load(skillsFile)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(skillsFile); err == nil {
+		fmt.Printf("Skills file: %s\n\n", skillsFile)
+	} else {
+		fmt.Printf("Skills file: %s (not found, using built-in mappings)\n\n", skillsFile)
+	}
+
+	var resolutions []skillmap.Resolution
+	switch {
+	case showAllSkills:
+		resolutions = skillMap.All()
+	case len(args) > 0:
+		for _, skill := range args {
+			resolutions = append(resolutions, skillMap.Resolve(skill))
+		}
+	default:
+		profileSkills := viper.GetStringSlice("profile.skills")
+		if len(profileSkills) == 0 {
+			return fmt.Errorf("no skills specified\n  Pass skills as arguments or set profile.skills in config file")
+		}
+		for _, skill := range profileSkills {
+			resolutions = append(resolutions, skillMap.Resolve(skill))
+		}
+	}
+
+	for _, resolution := range resolutions {
+		fmt.Printf("%s (%s)\n", resolution.Skill, resolution.Source)
+
+		if aliases := skillMap.Aliases(resolution.Canonical); len(aliases) > 0 {
+			fmt.Printf("  Aliases: %s\n", strings.Join(aliases, ", "))
+		}
+
+		for _, qualifier := range resolution.Qualifiers {
+			fmt.Printf("  %s\n", qualifier)
+		}
+	}
+
+	return nil
+}
+
+func getSkillsFilePath() string {
+	skillsFile := viper.GetString("preferences.skills_file")
+	if skillsFile != "" {
+		return expandPath(skillsFile)
+	}
+
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".issue-finder-skills.yaml")
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ashishra0/issue-finder/internal/gitea"
+	"github.com/ashishra0/issue-finder/internal/github"
+	"github.com/ashishra0/issue-finder/internal/gitlab"
+	"github.com/ashishra0/issue-finder/internal/skillmap"
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// buildSources creates the issue sources enabled in the config. GitHub is
+// enabled unless sources.github.enabled is false; GitLab and Gitea (or
+// Forgejo) instances are listed under sources.gitlab and sources.gitea.
+// When watch_repos is set, only those GitHub repositories are scanned.
+func buildSources() ([]source.IssueSource, error) {
+	searchConfig, err := loadSearchConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	skillMap, err := skillmap.Load(getSkillsFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	// The allow and deny lists apply to every source
+	if err := source.ValidateRepoFilters(loadFilters()); err != nil {
+		return nil, err
+	}
+
+	// Watched repositories are scanned on GitHub only, instead of searching
+	if len(viper.GetStringSlice("watch_repos")) > 0 {
+		ghClient, err := buildGitHubSource(searchConfig, skillMap)
+		if err != nil {
+			return nil, err
+		}
+		return []source.IssueSource{ghClient}, nil
+	}
+
+	sources := []source.IssueSource{}
+
+	if !viper.IsSet("sources.github.enabled") || viper.GetBool("sources.github.enabled") {
+		ghClient, err := buildGitHubSource(searchConfig, skillMap)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, ghClient)
+	}
+
+	var gitlabConfigs []types.ForgeConfig
+	if err := viper.UnmarshalKey("sources.gitlab", &gitlabConfigs); err != nil {
+		return nil, fmt.Errorf("invalid sources.gitlab config: %w", err)
+	}
+
+	for _, forge := range gitlabConfigs {
+		tokenEnv := forge.TokenEnv
+		if tokenEnv == "" {
+			tokenEnv = "GITLAB_TOKEN"
+		}
+
+		sources = append(sources, gitlab.NewClient(forge.Name, forge.BaseURL, os.Getenv(tokenEnv),
+			gitlab.WithSearchConfig(searchConfig),
+			gitlab.WithSkillMap(skillMap),
+			gitlab.WithProjectsPerQuery(forge.ProjectsPerQuery),
+		))
+	}
+
+	var giteaConfigs []types.ForgeConfig
+	if err := viper.UnmarshalKey("sources.gitea", &giteaConfigs); err != nil {
+		return nil, fmt.Errorf("invalid sources.gitea config: %w", err)
+	}
+
+	for _, forge := range giteaConfigs {
+		tokenEnv := forge.TokenEnv
+		if tokenEnv == "" {
+			tokenEnv = "GITEA_TOKEN"
+		}
+
+		sources = append(sources, gitea.NewClient(forge.Name, forge.BaseURL, os.Getenv(tokenEnv),
+			gitea.WithSearchConfig(searchConfig),
+			gitea.WithSkillMap(skillMap),
+			gitea.WithReposPerQuery(forge.ProjectsPerQuery),
+		))
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no issue sources enabled\n  Enable sources.github or add a sources.gitlab or sources.gitea entry in config file")
+	}
+
+	seen := make(map[string]bool)
+	for _, src := range sources {
+		if seen[src.Name()] {
+			return nil, fmt.Errorf("duplicate issue source name %q\n  Set a distinct name for each source in config file", src.Name())
+		}
+		seen[src.Name()] = true
+	}
+
+	return sources, nil
+}
+
+func buildGitHubSource(searchConfig types.SearchConfig, skillMap *skillmap.Map) (*github.Client, error) {
+	appAuth, err := loadGitHubApp()
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []github.Token
+	if appAuth == nil {
+		tokens, err = loadGitHubTokens()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	claimed := viper.GetString("filters.claimed")
+	if claimed != "" && claimed != github.ClaimedDrop && claimed != github.ClaimedFlag && claimed != github.ClaimedKeep {
+		return nil, fmt.Errorf("unknown filters.claimed %q (expected %q, %q or %q)", claimed, github.ClaimedDrop, github.ClaimedFlag, github.ClaimedKeep)
+	}
+
+	filters := loadFilters()
+
+	backend := viper.GetString("api.github_backend")
+	if backend != "" && backend != github.BackendREST && backend != github.BackendGraphQL {
+		return nil, fmt.Errorf("unknown api.github_backend %q (expected %q or %q)", backend, github.BackendREST, github.BackendGraphQL)
+	}
+
+	ghOptions := []github.Option{
+		github.WithBaseURL(viper.GetString("api.github_base_url")),
+		github.WithBackend(backend),
+		github.WithSearchConfig(searchConfig),
+		github.WithSkillMap(skillMap),
+		github.WithRetryPolicy(viper.GetInt("api.github_max_retries"), viper.GetDuration("api.github_retry_backoff")),
+		github.WithConcurrency(viper.GetInt("search.concurrency")),
+		github.WithFilters(filters),
+		github.WithTokens(tokens),
+	}
+	if repos := viper.GetStringSlice("watch_repos"); len(repos) > 0 {
+		for _, repo := range repos {
+			if err := github.ValidateRepoName(repo); err != nil {
+				return nil, fmt.Errorf("invalid watch_repos: %w", err)
+			}
+		}
+		ghOptions = append(ghOptions, github.WithWatchRepos(repos))
+	}
+	if cacheEnabled() {
+		ghOptions = append(ghOptions, github.WithCache(getCacheDir(), viper.GetDuration("cache.ttl")))
+	}
+
+	if appAuth != nil {
+		ghOptions = append(ghOptions, github.WithAppAuth(appAuth))
+		return github.NewClient("", ghOptions...), nil
+	}
+
+	return github.NewClient(tokens[0].Value, ghOptions...), nil
+}
+
+// loadGitHubApp reads the GitHub App configured under api.github_app, or
+// returns nil when no app is configured
+func loadGitHubApp() (*github.AppAuth, error) {
+	var app types.GitHubAppConfig
+	if err := viper.UnmarshalKey("api.github_app", &app); err != nil {
+		return nil, fmt.Errorf("invalid api.github_app config: %w", err)
+	}
+
+	if app.AppID == "" {
+		return nil, nil
+	}
+
+	var privateKey []byte
+	switch {
+	case app.PrivateKeyPath != "":
+		data, err := os.ReadFile(expandPath(app.PrivateKeyPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+		privateKey = data
+	case app.PrivateKeyEnv != "":
+		privateKey = []byte(os.Getenv(app.PrivateKeyEnv))
+		if len(privateKey) == 0 {
+			return nil, fmt.Errorf("%s environment variable not set", app.PrivateKeyEnv)
+		}
+	default:
+		return nil, fmt.Errorf("no GitHub App private key configured\n  Set api.github_app.private_key_path or api.github_app.private_key_env in config file")
+	}
+
+	appAuth, err := github.NewAppAuth(app.AppID, app.InstallationID, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api.github_app config: %w", err)
+	}
+	return appAuth, nil
+}
+
+// loadGitHubTokens reads the GitHub token pool from the environment
+// variables listed in api.github_token_envs (default api.github_token_env)
+// and from api.github_tokens_file, which holds one token per line
+func loadGitHubTokens() ([]github.Token, error) {
+	defaultEnv := getEnvVarName("api.github_token_env", "GITHUB_TOKEN")
+
+	envs := viper.GetStringSlice("api.github_token_envs")
+	if len(envs) == 0 {
+		envs = []string{defaultEnv}
+	}
+
+	tokens := []github.Token{}
+	seen := make(map[string]bool)
+	add := func(name, value string) {
+		if value == "" || seen[value] {
+			return
+		}
+		seen[value] = true
+		tokens = append(tokens, github.Token{Name: name, Value: value})
+	}
+
+	for _, env := range envs {
+		add(env, os.Getenv(env))
+	}
+
+	if tokensFile := viper.GetString("api.github_tokens_file"); tokensFile != "" {
+		data, err := os.ReadFile(expandPath(tokensFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub tokens file: %w", err)
+		}
+
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			add(fmt.Sprintf("%s:%d", filepath.Base(tokensFile), i+1), line)
+		}
+	}
+
+	if len(tokens) == 0 {
+		if !viper.IsSet("api.github_token_envs") && !viper.IsSet("api.github_tokens_file") {
+			return nil, fmt.Errorf("%s environment variable not set", defaultEnv)
+		}
+		return nil, fmt.Errorf("no GitHub tokens found\n  Set the variables in api.github_token_envs or add tokens to api.github_tokens_file")
+	}
+
+	return tokens, nil
+}
+
+// detectClaims runs claim detection on the issues of each source that
+// supports it
+func detectClaims(ctx context.Context, sources []source.IssueSource, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error) {
+	return checkPerSource(ctx, sources, issues, func(src source.IssueSource) issueCheck {
+		if detector, ok := src.(source.ClaimDetector); ok {
+			return detector.DetectClaims
+		}
+		return nil
+	})
+}
+
+// filterRepositories applies the repository health thresholds to the issues
+// of each source that supports them
+func filterRepositories(ctx context.Context, sources []source.IssueSource, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error) {
+	return checkPerSource(ctx, sources, issues, func(src source.IssueSource) issueCheck {
+		if checker, ok := src.(source.RepoHealthChecker); ok {
+			return checker.FilterRepositories
+		}
+		return nil
+	})
+}
+
+// issueCheck drops some of a source's issues and returns the issues to keep,
+// how many were dropped and any errors
+type issueCheck func(ctx context.Context, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error)
+
+// checkPerSource hands each source its own issues to the check checkFor
+// returns for it. Issues of sources without a check are kept.
+func checkPerSource(ctx context.Context, sources []source.IssueSource, issues []types.CandidateIssue, checkFor func(source.IssueSource) issueCheck) ([]types.CandidateIssue, int, error) {
+	bySource := make(map[string][]types.CandidateIssue)
+	for _, issue := range issues {
+		bySource[issue.Source] = append(bySource[issue.Source], issue)
+	}
+
+	kept := []types.CandidateIssue{}
+	dropped := 0
+	var errs []error
+
+	for _, src := range sources {
+		sourceIssues := bySource[src.Name()]
+		delete(bySource, src.Name())
+
+		check := checkFor(src)
+		if check == nil || len(sourceIssues) == 0 {
+			kept = append(kept, sourceIssues...)
+			continue
+		}
+
+		sourceKept, sourceDropped, err := check(ctx, sourceIssues)
+		kept = append(kept, sourceKept...)
+		dropped += sourceDropped
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, sourceIssues := range bySource {
+		kept = append(kept, sourceIssues...)
+	}
+
+	return kept, dropped, errors.Join(errs...)
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// DefaultAnthropicModel is used when no model is configured
+const DefaultAnthropicModel = "claude-sonnet-4-5-20250929"
+
+// AnthropicEvaluator evaluates issues with Claude through the Anthropic API
+type AnthropicEvaluator struct {
+	client *anthropic.Client
+	params Params
+}
+
+// NewAnthropicEvaluator creates an evaluator for the Anthropic API. Zero
+// params use the defaults.
+func NewAnthropicEvaluator(apiKey string, params Params) *AnthropicEvaluator {
+	params = params.withDefaults(DefaultAnthropicModel)
+
+	opts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if params.Timeout > 0 {
+		opts = append(opts, option.WithRequestTimeout(params.Timeout))
+	}
+
+	client := anthropic.NewClient(opts...)
+	return &AnthropicEvaluator{
+		client: &client,
+		params: params,
+	}
+}
+
+// EvaluateIssues sends issues to Claude for evaluation and returns matches
+func (e *AnthropicEvaluator) EvaluateIssues(ctx context.Context, profile types.UserProfile, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	request := anthropic.MessageNewParams{
+		Model:     anthropic.Model(e.params.Model),
+		MaxTokens: int64(e.params.MaxTokens),
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(buildPrompt(profile, issues, e.params.MaxMatches))),
+		},
+	}
+	if e.params.Temperature != nil {
+		request.Temperature = anthropic.Float(*e.params.Temperature)
+	}
+
+	// Claude must answer by calling the tool, whose input follows matchSchema
+	request.Tools = []anthropic.ToolUnionParam{{
+		OfTool: &anthropic.ToolParam{
+			Name:        matchesToolName,
+			Description: anthropic.String("Record the issues that are good first contributions for the developer"),
+			InputSchema: anthropic.ToolInputSchemaParam{
+				Properties: matchSchema["properties"],
+				Required:   []string{"matches"},
+			},
+		},
+	}}
+	request.ToolChoice = anthropic.ToolChoiceParamOfTool(matchesToolName)
+
+	response, err := e.client.Messages.New(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error calling Claude: %w", err)
+	}
+
+	truncated := response.StopReason == anthropic.StopReasonMaxTokens
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		switch block.Type {
+		case "tool_use":
+			if block.Name == matchesToolName {
+				return parseResponse(string(block.Input), issues, truncated, e.params, parseMatches)
+			}
+		case "text":
+			text.WriteString(block.Text)
+		}
+	}
+
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("Claude returned neither a %s call nor text", matchesToolName)
+	}
+
+	log.Printf("Warning: Claude answered without calling %s; parsing its text instead", matchesToolName)
+	return parseResponse(text.String(), issues, truncated, e.params, parseMatches)
+}
@@ -0,0 +1,201 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// Default token budgets for the issues of one batch. Local models often run
+// with a context window of only a few thousand tokens.
+const (
+	DefaultBatchTokens      = 20000
+	DefaultLocalBatchTokens = 3000
+)
+
+// ChunkConfig controls how a ChunkedEvaluator splits candidates. Zero values
+// use the defaults.
+type ChunkConfig struct {
+	// BatchTokens is the estimated prompt size of the issues in one batch
+	BatchTokens int
+	// Concurrency is the number of batches evaluated at the same time
+	Concurrency int
+	// MaxMatches is the number of matches kept after the ranking pass; it
+	// should match the MaxMatches of the wrapped evaluator
+	MaxMatches int
+}
+
+// ChunkedEvaluator wraps an Evaluator so that any number of candidates can be
+// evaluated. Candidates are split into batches that fit a token budget, each
+// batch is evaluated on its own, and the winners of all batches are ranked
+// against each other in a final pass, so the result no longer depends on how
+// many candidates the search produced.
+type ChunkedEvaluator struct {
+	evaluator Evaluator
+	config    ChunkConfig
+}
+
+// NewChunkedEvaluator wraps evaluator with the given batching settings
+func NewChunkedEvaluator(evaluator Evaluator, config ChunkConfig) *ChunkedEvaluator {
+	if config.BatchTokens <= 0 {
+		config.BatchTokens = DefaultBatchTokens
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.MaxMatches <= 0 {
+		config.MaxMatches = DefaultMaxMatches
+	}
+
+	return &ChunkedEvaluator{
+		evaluator: evaluator,
+		config:    config,
+	}
+}
+
+// BatchCount returns the number of batches issues are split into
+func (c *ChunkedEvaluator) BatchCount(issues []types.CandidateIssue) int {
+	return len(c.batches(issues))
+}
+
+// EvaluateIssues evaluates issues in batches and ranks the batch winners.
+// Candidates that fit a single batch are passed straight through. When the
+// winners themselves don't fit one batch, they are batched and ranked again.
+func (c *ChunkedEvaluator) EvaluateIssues(ctx context.Context, profile types.UserProfile, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	candidates := issues
+
+	for {
+		batches := c.batches(candidates)
+		if len(batches) <= 1 {
+			return c.evaluator.EvaluateIssues(ctx, profile, candidates)
+		}
+
+		log.Printf("Evaluating %d issues in %d batches", len(candidates), len(batches))
+
+		matches, err := c.evaluateBatches(ctx, profile, batches)
+		if err != nil {
+			return nil, err
+		}
+
+		// Few enough winners need no ranking pass
+		if len(matches) <= c.config.MaxMatches {
+			return matches, nil
+		}
+
+		winners := matchedCandidates(candidates, matches)
+		if len(winners) >= len(candidates) {
+			// Batches too small to narrow anything down; keep the first picks
+			return matches[:c.config.MaxMatches], nil
+		}
+
+		log.Printf("Ranking %d batch winners", len(winners))
+		candidates = winners
+	}
+}
+
+// evaluateBatches evaluates batches with at most Concurrency in flight and
+// returns their matches in batch order. The first failure cancels the
+// remaining batches, since a partial evaluation can't be saved.
+func (c *ChunkedEvaluator) evaluateBatches(ctx context.Context, profile types.UserProfile, batches [][]types.CandidateIssue) ([]types.IssueMatch, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]types.IssueMatch, len(batches))
+	errs := make([]error, len(batches))
+	slots := make(chan struct{}, c.config.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+
+			results[i], errs[i] = c.evaluator.EvaluateIssues(ctx, profile, batch)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Report the failure that caused the cancellation, not its echoes
+	for _, wantCanceled := range []bool{false, true} {
+		for i, err := range errs {
+			if err != nil && errors.Is(err, context.Canceled) == wantCanceled {
+				return nil, fmt.Errorf("batch %d of %d: %w", i+1, len(batches), err)
+			}
+		}
+	}
+
+	matches := []types.IssueMatch{}
+	for _, batchMatches := range results {
+		matches = append(matches, batchMatches...)
+	}
+	return matches, nil
+}
+
+// batches splits issues into consecutive batches whose estimated size stays
+// within the token budget. An issue larger than the budget gets a batch of
+// its own.
+func (c *ChunkedEvaluator) batches(issues []types.CandidateIssue) [][]types.CandidateIssue {
+	batches := [][]types.CandidateIssue{}
+	var current []types.CandidateIssue
+	tokens := 0
+
+	for _, issue := range issues {
+		size := estimateTokens(issue)
+		if len(current) > 0 && tokens+size > c.config.BatchTokens {
+			batches = append(batches, current)
+			current = nil
+			tokens = 0
+		}
+		current = append(current, issue)
+		tokens += size
+	}
+
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// estimateTokens approximates the prompt tokens an issue takes, at roughly
+// four characters of JSON per token
+func estimateTokens(issue types.CandidateIssue) int {
+	data, _ := json.Marshal(issue)
+	return len(data)/4 + 1
+}
+
+// matchedCandidates returns the candidates that matches refer to, in match
+// order. Matches for issues that weren't candidates are dropped.
+func matchedCandidates(candidates []types.CandidateIssue, matches []types.IssueMatch) []types.CandidateIssue {
+	byURL := make(map[string]types.CandidateIssue, len(candidates))
+	for _, issue := range candidates {
+		byURL[issue.URL] = issue
+	}
+
+	winners := []types.CandidateIssue{}
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		issue, ok := byURL[match.URL]
+		if !ok || seen[match.URL] {
+			continue
+		}
+		seen[match.URL] = true
+		winners = append(winners, issue)
+	}
+	return winners
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// fakeEvaluator matches the first pick issues of every batch and records the
+// batches it was given
+type fakeEvaluator struct {
+	pick    int
+	failURL string
+
+	mu      sync.Mutex
+	batches [][]types.CandidateIssue
+}
+
+func (f *fakeEvaluator) EvaluateIssues(ctx context.Context, profile types.UserProfile, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	f.mu.Lock()
+	f.batches = append(f.batches, issues)
+	f.mu.Unlock()
+
+	matches := []types.IssueMatch{}
+	for i, issue := range issues {
+		if issue.URL == f.failURL {
+			return nil, errBatchFailed
+		}
+		if i < f.pick {
+			matches = append(matches, types.IssueMatch{URL: issue.URL})
+		}
+	}
+	return matches, nil
+}
+
+var errBatchFailed = errors.New("model unavailable")
+
+// sameSizeIssues returns n issues with the same estimated token count
+func sameSizeIssues(n int) []types.CandidateIssue {
+	issues := make([]types.CandidateIssue, n)
+	for i := range issues {
+		issues[i] = types.CandidateIssue{
+			Repo:   "o/r",
+			Number: 10 + i,
+			URL:    fmt.Sprintf("https://github.com/o/r/issues/%d", 10+i),
+			Body:   strings.Repeat("x", 400),
+		}
+	}
+	return issues
+}
+
+func TestChunkedEvaluatorSplitsBatches(t *testing.T) {
+	issues := sameSizeIssues(10)
+	size := estimateTokens(issues[0])
+
+	fake := &fakeEvaluator{}
+	chunked := NewChunkedEvaluator(fake, ChunkConfig{BatchTokens: 3 * size})
+
+	if got := chunked.BatchCount(issues); got != 4 {
+		t.Errorf("BatchCount() = %d, want 4", got)
+	}
+
+	if _, err := chunked.EvaluateIssues(context.Background(), types.UserProfile{}, issues); err != nil {
+		t.Fatalf("EvaluateIssues() error = %v", err)
+	}
+
+	evaluated := 0
+	for _, batch := range fake.batches {
+		if len(batch) > 3 {
+			t.Errorf("batch of %d issues exceeds the token budget", len(batch))
+		}
+		evaluated += len(batch)
+	}
+	if len(fake.batches) != 4 || evaluated != 10 {
+		t.Errorf("evaluated %d issues in %d batches, want 10 in 4", evaluated, len(fake.batches))
+	}
+}
+
+func TestChunkedEvaluatorPassesSingleBatchThrough(t *testing.T) {
+	issues := sameSizeIssues(3)
+
+	fake := &fakeEvaluator{pick: 3}
+	chunked := NewChunkedEvaluator(fake, ChunkConfig{MaxMatches: 2})
+
+	matches, err := chunked.EvaluateIssues(context.Background(), types.UserProfile{}, issues)
+	if err != nil {
+		t.Fatalf("EvaluateIssues() error = %v", err)
+	}
+	// Trimming to MaxMatches is the wrapped evaluator's job
+	if len(fake.batches) != 1 || len(matches) != 3 {
+		t.Errorf("got %d matches from %d calls, want the evaluator called once", len(matches), len(fake.batches))
+	}
+}
+
+func TestChunkedEvaluatorSkipsRankingForFewWinners(t *testing.T) {
+	issues := sameSizeIssues(10)
+
+	fake := &fakeEvaluator{pick: 1}
+	chunked := NewChunkedEvaluator(fake, ChunkConfig{BatchTokens: 3 * estimateTokens(issues[0]), MaxMatches: 5})
+
+	matches, err := chunked.EvaluateIssues(context.Background(), types.UserProfile{}, issues)
+	if err != nil {
+		t.Fatalf("EvaluateIssues() error = %v", err)
+	}
+	if len(fake.batches) != 4 || len(matches) != 4 {
+		t.Errorf("got %d matches from %d calls, want one match per batch and no ranking pass", len(matches), len(fake.batches))
+	}
+}
+
+func TestChunkedEvaluatorRanksWinners(t *testing.T) {
+	issues := sameSizeIssues(10)
+
+	fake := &fakeEvaluator{pick: 2}
+	chunked := NewChunkedEvaluator(fake, ChunkConfig{BatchTokens: 3 * estimateTokens(issues[0]), MaxMatches: 3})
+
+	matches, err := chunked.EvaluateIssues(context.Background(), types.UserProfile{}, issues)
+	if err != nil {
+		t.Fatalf("EvaluateIssues() error = %v", err)
+	}
+	if len(matches) != 3 {
+		t.Errorf("got %d matches, want MaxMatches after ranking", len(matches))
+	}
+
+	// 10 candidates, then 7, 5 and 4 winners are ranked until 3 remain
+	if len(fake.batches) != 11 {
+		t.Errorf("evaluator called %d times, want 11", len(fake.batches))
+	}
+}
+
+func TestChunkedEvaluatorStopsWhenWinnersDontShrink(t *testing.T) {
+	issues := sameSizeIssues(4)
+
+	// Every issue wins its batch of one, so ranking would never narrow them down
+	fake := &fakeEvaluator{pick: 1}
+	chunked := NewChunkedEvaluator(fake, ChunkConfig{BatchTokens: 1, MaxMatches: 2})
+
+	matches, err := chunked.EvaluateIssues(context.Background(), types.UserProfile{}, issues)
+	if err != nil {
+		t.Fatalf("EvaluateIssues() error = %v", err)
+	}
+	if len(fake.batches) != 4 || len(matches) != 2 || matches[0].URL != issues[0].URL {
+		t.Errorf("got %d matches from %d calls, want the first 2 picks without ranking", len(matches), len(fake.batches))
+	}
+}
+
+func TestChunkedEvaluatorReportsBatchError(t *testing.T) {
+	issues := sameSizeIssues(10)
+
+	fake := &fakeEvaluator{pick: 1, failURL: issues[4].URL}
+	chunked := NewChunkedEvaluator(fake, ChunkConfig{BatchTokens: 3 * estimateTokens(issues[0]), Concurrency: 2})
+
+	matches, err := chunked.EvaluateIssues(context.Background(), types.UserProfile{}, issues)
+	if !errors.Is(err, errBatchFailed) {
+		t.Fatalf("EvaluateIssues() error = %v, want the batch failure", err)
+	}
+	if !strings.Contains(err.Error(), "batch 2 of 4") || matches != nil {
+		t.Errorf("EvaluateIssues() = %v, %v; want no matches and the failing batch named", matches, err)
+	}
+}
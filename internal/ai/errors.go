@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// APIError is returned when an LLM API answers with a non-200 status
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error %d: %s", e.Provider, e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// ParseError is returned when a model response doesn't have the structure
+// of matchSchema, even after falling back to the JSON in its text. Response
+// holds the raw text for debugging; Truncated is set when the response was
+// cut off at the token limit.
+type ParseError struct {
+	Response  string
+	Err       error
+	Truncated bool
+}
+
+func (e *ParseError) Error() string {
+	if e.Truncated {
+		return fmt.Sprintf("model response was cut off at the token limit (raise ai.max_tokens): %v", e.Err)
+	}
+	return fmt.Sprintf("model response does not contain the expected matches: %v", e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -3,51 +3,120 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
-	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/ashishra0/issue-finder/pkg/types"
 )
 
-type Evaluator struct {
-	client *anthropic.Client
+// Provider names accepted in the ai.provider config key
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+	ProviderOllama    = "ollama"
+)
+
+// DefaultMaxTokens bounds the response length of hosted models
+const DefaultMaxTokens = 4096
+
+// DefaultMaxMatches is how many matches an evaluation returns at most
+const DefaultMaxMatches = 5
+
+// Params are the model and sampling settings shared by all providers. Zero
+// values use the provider's defaults.
+type Params struct {
+	Model     string
+	MaxTokens int
+	// Temperature is nil to use the provider's default
+	Temperature *float64
+	// Timeout bounds a single evaluation request
+	Timeout time.Duration
+	// MaxMatches is the number of best matches to return
+	MaxMatches int
+}
+
+// withDefaults fills in the settings shared by all providers
+func (p Params) withDefaults(model string) Params {
+	if p.Model == "" {
+		p.Model = model
+	}
+	if p.MaxTokens == 0 {
+		p.MaxTokens = DefaultMaxTokens
+	}
+	if p.MaxMatches == 0 {
+		p.MaxMatches = DefaultMaxMatches
+	}
+	return p
 }
 
-func NewEvaluator(apiKey string) *Evaluator {
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
-	return &Evaluator{
-		client: &client,
+// DefaultModel returns the model a provider uses when none is configured
+func DefaultModel(provider string) string {
+	switch provider {
+	case ProviderOpenAI:
+		return DefaultOpenAIModel
+	case ProviderOllama:
+		return DefaultOllamaModel
+	default:
+		return DefaultAnthropicModel
 	}
 }
 
-// EvaluateIssues sends issues to Claude for evaluation and returns matches
-func (e *Evaluator) EvaluateIssues(profile types.UserProfile, issues []map[string]interface{}) []types.IssueMatch {
-	ctx := context.Background()
+// ValidateParams checks params against the ranges provider accepts
+func ValidateParams(provider string, p Params) error {
+	if p.MaxTokens < 0 {
+		return fmt.Errorf("ai.max_tokens must not be negative")
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("ai.timeout must not be negative")
+	}
+	if p.MaxMatches < 0 {
+		return fmt.Errorf("ai.max_matches must not be negative")
+	}
+
+	if p.Temperature != nil {
+		// Anthropic samples in [0, 1], OpenAI-compatible APIs in [0, 2]
+		maxTemperature := 2.0
+		if provider == ProviderAnthropic {
+			maxTemperature = 1.0
+		}
+		if *p.Temperature < 0 || *p.Temperature > maxTemperature {
+			return fmt.Errorf("ai.temperature must be between 0 and %g for %s", maxTemperature, provider)
+		}
+	}
+
+	return nil
+}
 
+// Evaluator picks the candidate issues that best match a developer profile
+type Evaluator interface {
+	EvaluateIssues(ctx context.Context, profile types.UserProfile, issues []types.CandidateIssue) ([]types.IssueMatch, error)
+}
+
+// buildPrompt renders the evaluation prompt shared by all providers
+func buildPrompt(profile types.UserProfile, issues []types.CandidateIssue, maxMatches int) string {
 	profileJSON, _ := json.Marshal(profile)
 	issuesJSON, _ := json.Marshal(issues)
 
-	prompt := fmt.Sprintf(`You are helping find GitHub OSS contribution opportunities for a developer with %d years of experience.
+	return fmt.Sprintf(`You are helping find OSS contribution opportunities for a developer with %d years of experience.
 
 Developer Profile:
 %s
 
-GitHub Issues to evaluate:
+Issues to evaluate (from GitHub and possibly other forges):
 %s
 
-Your task: Carefully evaluate each issue and return ONLY the best 3-5 matches that would be genuinely good first contributions.
+Your task: Carefully evaluate each issue and return ONLY the best matches (at most %d) that would be genuinely good first contributions.
 
 Selection criteria (ALL must be met):
 1. Clear scope: The issue has a well-defined problem and expected outcome
 2. Skill match: Requires skills the developer has
 3. Appropriate complexity: Not trivial, but achievable in a few hours to a day
-4. Active project: The issue has recent activity and the project seems maintained
+4. Active project: The issue has recent activity and the project seems maintained (use the repository stars, forks and pushed_at where provided)
 5. Welcoming: Issue description is friendly and provides context
 6. Realistic: Avoid issues that are too vague, too large, or require deep domain knowledge
+7. Available: Skip issues with a "claimed" note, an open linked pull request, or recent comments from someone already working on it
 
 For each match, provide a SPECIFIC reason explaining:
 - What skill(s) from their profile apply
@@ -70,62 +139,46 @@ Return ONLY matching issues in this JSON format:
   ]
 }
 
-Be VERY selective - quality over quantity. Return at most 5 matches. If no issues are genuinely good fits, return empty matches array.`,
-		profile.ExperienceYears, string(profileJSON), string(issuesJSON))
-
-	response, err := e.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     "claude-sonnet-4-5-20250929",
-		MaxTokens: 4096,
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-		},
-	})
-
-	if err != nil {
-		log.Printf("Error calling Claude: %v", err)
-		return []types.IssueMatch{}
-	}
-
-	contentText := response.Content[0].Text
-
-	jsonText := extractJSON(contentText)
+Be VERY selective - quality over quantity. Return at most %d matches. If no issues are genuinely good fits, return empty matches array.`,
+		profile.ExperienceYears, string(profileJSON), string(issuesJSON), maxMatches, maxMatches)
+}
 
-	var result struct {
-		Matches []types.IssueMatch `json:"matches"`
+// parseMatches decodes the matches from a model response: the input of a
+// tool call, structured output, or as a fallback the first JSON object with
+// matches in free text
+func parseMatches(text string, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	object := matchesObject(text)
+	if object == "" {
+		return nil, &ParseError{Response: text, Err: fmt.Errorf(`no JSON object with "matches" found`)}
 	}
 
-	err = json.Unmarshal([]byte(jsonText), &result)
+	matches, err := decodeMatches(object, issues)
 	if err != nil {
-		log.Printf("Error parsing Claude response: %v", err)
-		log.Printf("Response text: %s", contentText)
-		return []types.IssueMatch{}
-	}
-
-	now := time.Now().Format("2006-01-02 15:04")
-	for i := range result.Matches {
-		result.Matches[i].FoundAt = now
+		return nil, &ParseError{Response: text, Err: err}
 	}
 
-	return result.Matches
+	return matches, nil
 }
 
-// extractJSON removes markdown code block wrapping from JSON responses
-func extractJSON(text string) string {
-	text = strings.TrimSpace(text)
+// parseResponse parses a model response with parse and keeps at most
+// params.MaxMatches matches. A response that was cut off at the token limit
+// is reported, and usually fails to parse; its ParseError is flagged so the
+// cause is clear.
+func parseResponse(text string, issues []types.CandidateIssue, truncated bool, params Params, parse func(string, []types.CandidateIssue) ([]types.IssueMatch, error)) ([]types.IssueMatch, error) {
+	if truncated {
+		log.Printf("Warning: the model response reached the %d token limit and may be incomplete; raise ai.max_tokens or evaluate fewer issues", params.MaxTokens)
+	}
 
-	if strings.HasPrefix(text, "```") {
-		startIdx := strings.Index(text, "\n")
-		if startIdx == -1 {
-			return text
-		}
+	matches, err := parse(text, issues)
 
-		endIdx := strings.LastIndex(text, "```")
-		if endIdx == -1 || endIdx <= startIdx {
-			return text
-		}
+	var parseErr *ParseError
+	if truncated && errors.As(err, &parseErr) {
+		parseErr.Truncated = true
+	}
 
-		return strings.TrimSpace(text[startIdx+1 : endIdx])
+	if params.MaxMatches > 0 && len(matches) > params.MaxMatches {
+		matches = matches[:params.MaxMatches]
 	}
 
-	return text
+	return matches, err
 }
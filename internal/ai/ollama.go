@@ -0,0 +1,261 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// DefaultOllamaBaseURL is where a local Ollama server listens by default
+const DefaultOllamaBaseURL = "http://localhost:11434"
+
+// DefaultOllamaModel is used when no model is configured
+const DefaultOllamaModel = "llama3.1:8b"
+
+// Local server APIs
+const (
+	// LocalAPIOllama is Ollama's native /api/chat endpoint
+	LocalAPIOllama = "ollama"
+	// LocalAPIOpenAI is the OpenAI-compatible /v1/chat/completions endpoint
+	// served by llama.cpp's server and by Ollama itself
+	LocalAPIOpenAI = "openai"
+)
+
+// localBodyLimit keeps issue bodies short for small context windows
+const localBodyLimit = 300
+
+// OllamaEvaluator evaluates issues with a model running on a local Ollama
+// or llama.cpp server, so neither issues nor the profile leave the machine.
+// Smaller models get a compact prompt that refers to issues by index, and
+// their answers are checked against the candidates.
+type OllamaEvaluator struct {
+	baseURL    string
+	api        string
+	params     Params
+	httpClient *http.Client
+}
+
+// NewOllamaEvaluator creates an evaluator for the local server at baseURL,
+// speaking LocalAPIOllama or LocalAPIOpenAI. Empty values use the defaults.
+func NewOllamaEvaluator(baseURL, api string, params Params) *OllamaEvaluator {
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+	if api == "" {
+		api = LocalAPIOllama
+	}
+	params = params.withDefaults(DefaultOllamaModel)
+	if params.Temperature == nil {
+		// Deterministic answers keep small models on the requested format
+		temperature := 0.0
+		params.Temperature = &temperature
+	}
+	if params.Timeout == 0 {
+		// Local models on laptop hardware can take minutes per answer
+		params.Timeout = 15 * time.Minute
+	}
+
+	return &OllamaEvaluator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		api:     api,
+		params:  params,
+		httpClient: &http.Client{
+			Timeout: params.Timeout,
+		},
+	}
+}
+
+type ollamaChatRequest struct {
+	Model    string         `json:"model"`
+	Messages []chatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Format   any            `json:"format,omitempty"`
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message    chatMessage `json:"message"`
+	DoneReason string      `json:"done_reason"`
+}
+
+// EvaluateIssues sends issues to the local model for evaluation and returns
+// the matches that refer to actual candidates
+func (e *OllamaEvaluator) EvaluateIssues(ctx context.Context, profile types.UserProfile, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	messages := []chatMessage{
+		{Role: "system", Content: compactSystemPrompt},
+		{Role: "user", Content: buildCompactPrompt(profile, issues, e.params.MaxMatches)},
+	}
+
+	var content string
+	var truncated bool
+	var err error
+	if e.api == LocalAPIOpenAI {
+		content, truncated, err = chatCompletion(ctx, e.httpClient, e.baseURL+"/v1", "", chatRequest{
+			Model:          e.params.Model,
+			Messages:       messages,
+			MaxTokens:      e.params.MaxTokens,
+			Temperature:    e.params.Temperature,
+			ResponseFormat: schemaFormat("issue_matches", compactMatchSchema),
+		})
+	} else {
+		content, truncated, err = e.chat(ctx, messages, compactMatchSchema)
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			// Ollama before 0.5 only accepts "json" as the format
+			log.Printf("Warning: %v; retrying without the response schema", err)
+			content, truncated, err = e.chat(ctx, messages, "json")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResponse(content, issues, truncated, e.params, parseCompactMatches)
+}
+
+// chat calls Ollama's native chat endpoint with the output constrained to
+// format, a JSON schema or "json", and returns the answer and whether it was
+// cut off at the token limit
+func (e *OllamaEvaluator) chat(ctx context.Context, messages []chatMessage, format any) (string, bool, error) {
+	payload, err := json.Marshal(ollamaChatRequest{
+		Model:    e.params.Model,
+		Messages: messages,
+		Format:   format,
+		Options: map[string]any{
+			"temperature": *e.params.Temperature,
+			"num_predict": e.params.MaxTokens,
+		},
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return "", false, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("error calling Ollama at %s (is it running?): %w", e.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", false, &APIError{Provider: "Ollama", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", false, fmt.Errorf("error decoding Ollama response: %w", err)
+	}
+
+	return result.Message.Content, result.DoneReason == "length", nil
+}
+
+const compactSystemPrompt = `You pick good first open source issues for a developer. Answer with a single JSON object and nothing else.`
+
+// buildCompactPrompt renders a shorter prompt for small local models. Issues
+// are numbered and trimmed to the fields that matter for the decision.
+func buildCompactPrompt(profile types.UserProfile, issues []types.CandidateIssue, maxMatches int) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Developer: %d years of experience. Skills: %s.", profile.ExperienceYears, strings.Join(profile.Skills, ", "))
+	if len(profile.Interests) > 0 {
+		fmt.Fprintf(&b, " Interests: %s.", strings.Join(profile.Interests, ", "))
+	}
+	b.WriteString("\n\nIssues:\n")
+
+	for i, issue := range issues {
+		fmt.Fprintf(&b, "\n[%d] %s: %s\n", i, issue.Repo, issue.Title)
+		if len(issue.Labels) > 0 {
+			fmt.Fprintf(&b, "Labels: %s\n", strings.Join(issue.Labels, ", "))
+		}
+		fmt.Fprintf(&b, "Comments: %d", issue.Comments)
+		if issue.Repository != nil {
+			fmt.Fprintf(&b, ", stars: %d", issue.Repository.Stars)
+		}
+		if issue.Claimed != "" {
+			fmt.Fprintf(&b, ", claimed: %s", issue.Claimed)
+		}
+		b.WriteString("\n")
+		if body := strings.TrimSpace(issue.Body); body != "" {
+			b.WriteString(issuebody.Truncate(body, localBodyLimit))
+			b.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&b, `
+Choose at most %d issues that match the developer's skills, have a clear scope, can be done in a few hours to a day, and are not already claimed. Choose none if nothing fits.
+
+Reply with JSON only, in exactly this shape:
+{"matches": [{"index": 0, "match_reason": "which skills apply and why the scope fits", "estimated_effort": "small"}]}
+
+"index" is the number in brackets. "estimated_effort" is "small", "medium" or "large".`, maxMatches)
+
+	return b.String()
+}
+
+// parseCompactMatches decodes an answer to the compact prompt. Small models
+// often wrap JSON in prose or invent issues, so the first JSON object with
+// matches is extracted and every match must point at a real candidate; the
+// issue details come from the candidate, not the model.
+func parseCompactMatches(text string, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	var result struct {
+		Matches *[]struct {
+			Index           *int   `json:"index"`
+			MatchReason     string `json:"match_reason"`
+			EstimatedEffort string `json:"estimated_effort"`
+		} `json:"matches"`
+	}
+
+	object := matchesObject(text)
+	if object == "" {
+		return nil, &ParseError{Response: text, Err: fmt.Errorf(`no JSON object with "matches" found`)}
+	}
+
+	if err := json.Unmarshal([]byte(object), &result); err != nil {
+		return nil, &ParseError{Response: text, Err: err}
+	}
+	if result.Matches == nil {
+		return nil, &ParseError{Response: text, Err: fmt.Errorf(`response has no "matches" array`)}
+	}
+
+	now := time.Now().Format("2006-01-02 15:04")
+	matches := []types.IssueMatch{}
+	seen := make(map[int]bool)
+
+	for _, m := range *result.Matches {
+		if m.Index == nil || *m.Index < 0 || *m.Index >= len(issues) {
+			log.Printf("Ignoring match for unknown issue index in model response")
+			continue
+		}
+		if seen[*m.Index] {
+			continue
+		}
+
+		match, err := newMatch(issues[*m.Index], m.MatchReason, m.EstimatedEffort, now)
+		if err != nil {
+			log.Printf("Ignoring match in model response: %v", err)
+			continue
+		}
+
+		seen[*m.Index] = true
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,168 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// DefaultOpenAIBaseURL is the API root of OpenAI itself
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// DefaultOpenAIModel is used when no model is configured
+const DefaultOpenAIModel = "gpt-4o-mini"
+
+// OpenAIEvaluator evaluates issues through an OpenAI-compatible chat
+// completions API, such as OpenAI itself or an internal gateway
+type OpenAIEvaluator struct {
+	baseURL    string
+	apiKey     string
+	params     Params
+	httpClient *http.Client
+}
+
+// NewOpenAIEvaluator creates an evaluator for the chat completions API at
+// baseURL (e.g. https://api.openai.com/v1). Empty values use the defaults.
+func NewOpenAIEvaluator(baseURL, apiKey string, params Params) *OpenAIEvaluator {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	params = params.withDefaults(DefaultOpenAIModel)
+	if params.Timeout == 0 {
+		params.Timeout = 5 * time.Minute
+	}
+
+	return &OpenAIEvaluator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		params:  params,
+		httpClient: &http.Client{
+			Timeout: params.Timeout,
+		},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *jsonSchemaFormat `json:"json_schema,omitempty"`
+}
+
+type jsonSchemaFormat struct {
+	Name   string         `json:"name"`
+	Strict bool           `json:"strict"`
+	Schema map[string]any `json:"schema"`
+}
+
+// schemaFormat requests structured output that strictly follows schema
+func schemaFormat(name string, schema map[string]any) *responseFormat {
+	return &responseFormat{
+		Type:       "json_schema",
+		JSONSchema: &jsonSchemaFormat{Name: name, Strict: true, Schema: schema},
+	}
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// EvaluateIssues sends issues to the chat completions API for evaluation
+// and returns matches
+func (e *OpenAIEvaluator) EvaluateIssues(ctx context.Context, profile types.UserProfile, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	content, truncated, err := chatCompletion(ctx, e.httpClient, e.baseURL, e.apiKey, chatRequest{
+		Model: e.params.Model,
+		Messages: []chatMessage{
+			{Role: "user", Content: buildPrompt(profile, issues, e.params.MaxMatches)},
+		},
+		MaxTokens:      e.params.MaxTokens,
+		Temperature:    e.params.Temperature,
+		ResponseFormat: schemaFormat("issue_matches", matchSchema),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResponse(content, issues, truncated, e.params, parseMatches)
+}
+
+// chatCompletion posts a request to an OpenAI-compatible chat completions
+// API and returns the content of the first choice, and whether it was cut
+// off at the token limit. Servers that reject the requested response format
+// are asked again without it, leaving the JSON to the prompt.
+func chatCompletion(ctx context.Context, httpClient *http.Client, baseURL, apiKey string, request chatRequest) (string, bool, error) {
+	content, truncated, err := postChatCompletion(ctx, httpClient, baseURL, apiKey, request)
+
+	var apiErr *APIError
+	if request.ResponseFormat != nil && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		log.Printf("Warning: %v; retrying without structured output", err)
+		request.ResponseFormat = nil
+		return postChatCompletion(ctx, httpClient, baseURL, apiKey, request)
+	}
+
+	return content, truncated, err
+}
+
+// postChatCompletion sends a single chat completions request
+func postChatCompletion(ctx context.Context, httpClient *http.Client, baseURL, apiKey string, request chatRequest) (string, bool, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", false, fmt.Errorf("error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", false, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("error calling chat completions API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", false, &APIError{Provider: "OpenAI-compatible", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", false, fmt.Errorf("error decoding chat completions response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", false, fmt.Errorf("chat completions API returned no choices")
+	}
+
+	choice := result.Choices[0]
+	return choice.Message.Content, choice.FinishReason == "length", nil
+}
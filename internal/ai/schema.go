@@ -0,0 +1,209 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// matchesToolName is the tool Claude is asked to call with its matches
+const matchesToolName = "record_matches"
+
+// effortLevels are the accepted estimated_effort values
+var effortLevels = []string{"small", "medium", "large"}
+
+// matchSchema is the JSON schema of an evaluation response. It is sent as a
+// tool input schema or response format so that the model answers with this
+// structure instead of free text.
+var matchSchema = objectSchema(map[string]any{
+	"matches": map[string]any{
+		"type": "array",
+		"items": objectSchema(map[string]any{
+			"repo":             stringSchema("Repository of the issue, as owner/name"),
+			"issue_number":     map[string]any{"type": "integer"},
+			"title":            stringSchema("Title of the issue"),
+			"url":              stringSchema("URL of the issue, exactly as given"),
+			"match_reason":     stringSchema("Which skills apply, why the scope fits and what makes it welcoming"),
+			"estimated_effort": map[string]any{"type": "string", "enum": effortLevels},
+			"labels":           map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"created_at":       stringSchema("Creation date of the issue, YYYY-MM-DD"),
+		}),
+	},
+})
+
+// compactMatchSchema is the response schema of the compact prompt used for
+// local models, which refers to issues by index
+var compactMatchSchema = objectSchema(map[string]any{
+	"matches": map[string]any{
+		"type": "array",
+		"items": objectSchema(map[string]any{
+			"index":            map[string]any{"type": "integer"},
+			"match_reason":     stringSchema("Which skills apply and why the scope fits"),
+			"estimated_effort": map[string]any{"type": "string", "enum": effortLevels},
+		}),
+	},
+})
+
+// objectSchema describes a closed object whose properties are all required,
+// as OpenAI's strict structured output demands
+func objectSchema(properties map[string]any) map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             slices.Sorted(maps.Keys(properties)),
+		"additionalProperties": false,
+	}
+}
+
+func stringSchema(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+// decodeMatches decodes a response that should follow matchSchema and checks
+// every match against the candidates. A match that breaks the schema or
+// refers to an issue that wasn't evaluated is dropped with a warning rather
+// than discarding the whole response; only a response without the expected
+// structure is an error.
+func decodeMatches(data string, issues []types.CandidateIssue) ([]types.IssueMatch, error) {
+	var response struct {
+		Matches *[]json.RawMessage `json:"matches"`
+	}
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		return nil, err
+	}
+	if response.Matches == nil {
+		return nil, fmt.Errorf(`response has no "matches" array`)
+	}
+
+	byURL := make(map[string]types.CandidateIssue, len(issues))
+	byNumber := make(map[string]types.CandidateIssue, len(issues))
+	for _, issue := range issues {
+		byURL[issue.URL] = issue
+		byNumber[fmt.Sprintf("%s#%d", issue.Repo, issue.Number)] = issue
+	}
+
+	now := time.Now().Format("2006-01-02 15:04")
+	matches := []types.IssueMatch{}
+	seen := make(map[string]bool)
+
+	for i, raw := range *response.Matches {
+		var m types.IssueMatch
+		if err := json.Unmarshal(raw, &m); err != nil {
+			log.Printf("Ignoring match %d in model response: %v", i+1, err)
+			continue
+		}
+
+		// Models sometimes reformat URLs, so fall back to the issue number
+		issue, ok := byURL[m.URL]
+		if !ok {
+			issue, ok = byNumber[fmt.Sprintf("%s#%d", m.Repo, m.IssueNumber)]
+		}
+		if !ok {
+			log.Printf("Ignoring match %d in model response: %s is not one of the evaluated issues", i+1, m.URL)
+			continue
+		}
+		if seen[issue.URL] {
+			continue
+		}
+
+		match, err := newMatch(issue, m.MatchReason, m.Effort, now)
+		if err != nil {
+			log.Printf("Ignoring match %d in model response: %v", i+1, err)
+			continue
+		}
+
+		seen[issue.URL] = true
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// newMatch builds the match for a candidate. Only the judgement comes from
+// the model; the issue details are copied from the candidate.
+func newMatch(issue types.CandidateIssue, reason, effort, foundAt string) (types.IssueMatch, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return types.IssueMatch{}, fmt.Errorf("match for %s has no match_reason", issue.URL)
+	}
+
+	effort = strings.ToLower(strings.TrimSpace(effort))
+	if !slices.Contains(effortLevels, effort) {
+		effort = "medium"
+	}
+
+	return types.IssueMatch{
+		Source:      issue.Source,
+		Repo:        issue.Repo,
+		IssueNumber: issue.Number,
+		Title:       issue.Title,
+		URL:         issue.URL,
+		MatchReason: reason,
+		Effort:      effort,
+		Labels:      issue.Labels,
+		CreatedAt:   issue.CreatedAt.Format("2006-01-02"),
+		FoundAt:     foundAt,
+	}, nil
+}
+
+// matchesObject returns the first balanced {...} object in text that has a
+// "matches" key, skipping any preamble or code fence, braces inside strings
+// and objects such as "{see below}" that are not the answer. It returns ""
+// if there is none.
+func matchesObject(text string) string {
+	for start := strings.Index(text, "{"); start != -1; {
+		object := balancedObject(text[start:])
+
+		var fields map[string]json.RawMessage
+		if object != "" && json.Unmarshal([]byte(object), &fields) == nil {
+			if _, ok := fields["matches"]; ok {
+				return object
+			}
+		}
+
+		next := strings.Index(text[start+1:], "{")
+		if next == -1 {
+			break
+		}
+		start += next + 1
+	}
+
+	return ""
+}
+
+// balancedObject returns the {...} object text starts with, or "" if its
+// braces are never balanced
+func balancedObject(text string) string {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return text[:i+1]
+			}
+		}
+	}
+
+	return ""
+}
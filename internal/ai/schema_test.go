@@ -0,0 +1,23 @@
+package ai
+
+import "testing"
+
+func TestMatchesObject(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: `{"matches":[]}`, want: `{"matches":[]}`},
+		{text: "```json\n{\"matches\":[{\"index\":0}]}\n```", want: `{"matches":[{"index":0}]}`},
+		{text: `Sure {see below}: {"matches":[]}`, want: `{"matches":[]}`},
+		{text: `Here is {"note":"x"} and {"matches":[{"match_reason":"uses {braces}"}]}`, want: `{"matches":[{"match_reason":"uses {braces}"}]}`},
+		{text: `{"matches":[{"index":0}`, want: ""},
+		{text: `No issues fit {sorry}`, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := matchesObject(tt.text); got != tt.want {
+			t.Errorf("matchesObject(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
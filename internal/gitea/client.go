@@ -0,0 +1,374 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/internal/skillmap"
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// DefaultBaseURL is the root of codeberg.org
+const DefaultBaseURL = "https://codeberg.org"
+
+const (
+	// maxPerPage is Gitea's default MAX_RESPONSE_ITEMS
+	maxPerPage           = 50
+	defaultReposPerQuery = 20
+)
+
+// Client searches a Gitea or Forgejo instance through its REST API (v1).
+// Like GitLab, Gitea has no global issue search by language, so each query
+// searches repositories by topic and then lists their open issues.
+type Client struct {
+	name          string
+	baseURL       string
+	api           *source.APIClient
+	search        types.SearchConfig
+	skillMap      *skillmap.Map
+	reposPerQuery int
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithSearchConfig sets the labels, date windows, comment bounds and
+// pagination limits. Per-skill overrides and min_reactions are not applied
+// on Gitea.
+func WithSearchConfig(cfg types.SearchConfig) Option {
+	return func(c *Client) {
+		c.search = cfg
+	}
+}
+
+// WithSkillMap sets how profile skills translate into repository searches.
+// "topic:" qualifiers search by topic; "language:" qualifiers search for a
+// topic of the same name, or narrow a topic search when both are present.
+func WithSkillMap(skillMap *skillmap.Map) Option {
+	return func(c *Client) {
+		if skillMap != nil {
+			c.skillMap = skillMap
+		}
+	}
+}
+
+// WithReposPerQuery limits how many repositories are scanned per query
+func WithReposPerQuery(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.reposPerQuery = n
+		}
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.api.HTTPClient = httpClient
+		}
+	}
+}
+
+// WithRetryPolicy sets how many times a rate limited request is retried and
+// the initial backoff used when Gitea sends no Retry-After
+func WithRetryPolicy(maxRetries int, baseBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.api.MaxRetries = maxRetries
+		c.api.BaseBackoff = baseBackoff
+	}
+}
+
+// NewClient creates a client for the Gitea instance at baseURL. An empty
+// name defaults to the host of baseURL.
+func NewClient(name, baseURL, token string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	if name == "" {
+		name = baseURL
+		if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+			name = parsed.Host
+		}
+	}
+
+	c := &Client{
+		name:    name,
+		baseURL: baseURL,
+		api: &source.APIClient{
+			Forge:      "Gitea",
+			HTTPClient: &http.Client{Timeout: 30 * time.Second},
+			Header:     http.Header{},
+		},
+		search: types.SearchConfig{
+			MaxPages:           1,
+			MaxResultsPerQuery: 50,
+		},
+		skillMap:      skillmap.Default(),
+		reposPerQuery: defaultReposPerQuery,
+	}
+
+	if token != "" {
+		c.api.Header.Set("Authorization", "token "+token)
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name identifies the source in state keys and output
+func (c *Client) Name() string {
+	return c.name
+}
+
+type repository struct {
+	FullName        string    `json:"full_name"`
+	Language        string    `json:"language"`
+	StarsCount      int       `json:"stars_count"`
+	ForksCount      int       `json:"forks_count"`
+	OpenIssuesCount int       `json:"open_issues_count"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	Archived        bool      `json:"archived"`
+	DefaultBranch   string    `json:"default_branch"`
+}
+
+type repoSearchResponse struct {
+	OK   bool         `json:"ok"`
+	Data []repository `json:"data"`
+}
+
+type issue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	Labels    []label   `json:"labels"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Comments  int       `json:"comments"`
+	Assignees []user    `json:"assignees"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+// query is one repository search combined with one label
+type query struct {
+	description string
+	topic       string
+	language    string
+	label       string
+}
+
+// FetchRelevantIssues lists open issues from repositories matching the
+// profile skills. Failed queries are reported in a *source.FetchError while
+// issues from the other queries are still returned.
+func (c *Client) FetchRelevantIssues(ctx context.Context, profile types.UserProfile) (source.Result, error) {
+	return source.CollectIssues(ctx, c.name, c.buildQueries(profile), func(q query) string { return q.description }, c.runQuery)
+}
+
+// buildQueries turns each skill qualifier into a repository search, once
+// per label
+func (c *Client) buildQueries(profile types.UserProfile) []query {
+	queries := []query{}
+
+	labels := c.search.Labels
+	if len(labels) == 0 {
+		labels = []string{""}
+	}
+
+	for _, skill := range profile.Skills {
+		for _, qualifier := range c.skillMap.Resolve(skill).Qualifiers {
+			topic, language := repoFilters(qualifier)
+			if topic == "" {
+				continue
+			}
+
+			for _, label := range labels {
+				description := qualifier
+				if label != "" {
+					description += fmt.Sprintf(" label:%q", label)
+				}
+				queries = append(queries, query{description: description, topic: topic, language: language, label: label})
+			}
+		}
+	}
+
+	return queries
+}
+
+// repoFilters maps a qualifier such as "language:ruby topic:rails" onto the
+// topic to search for and the language to narrow results by. Gitea can't
+// search repositories by language, so a lone language becomes the topic.
+func repoFilters(qualifier string) (topic, language string) {
+	for _, part := range strings.Fields(qualifier) {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "language":
+			language = value
+		case "topic":
+			topic = value
+		}
+	}
+
+	if topic == "" {
+		return language, ""
+	}
+	return topic, language
+}
+
+func (c *Client) runQuery(ctx context.Context, q query) ([]types.CandidateIssue, error) {
+	params := url.Values{}
+	params.Set("q", q.topic)
+	params.Set("topic", "true")
+	params.Set("sort", "updated")
+	params.Set("order", "desc")
+	params.Set("limit", strconv.Itoa(min(c.reposPerQuery, maxPerPage)))
+
+	var response repoSearchResponse
+	if _, err := c.getJSON(ctx, "/repos/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	candidates := []types.CandidateIssue{}
+	for _, repo := range response.Data {
+		if repo.Archived {
+			continue
+		}
+		if q.language != "" && !strings.EqualFold(repo.Language, q.language) {
+			continue
+		}
+
+		issues, err := c.listIssues(ctx, repo, q.label)
+		if err != nil {
+			return candidates, err
+		}
+		candidates = append(candidates, issues...)
+	}
+
+	return candidates, nil
+}
+
+// listIssues lists open issues of a repository, following pagination up to
+// the configured limits
+func (c *Client) listIssues(ctx context.Context, repo repository, label string) ([]types.CandidateIssue, error) {
+	maxResults := c.search.MaxResultsPerQuery
+	if maxResults <= 0 {
+		maxResults = maxPerPage
+	}
+	perPage := min(maxResults, maxPerPage)
+
+	params := url.Values{}
+	params.Set("state", "open")
+	params.Set("type", "issues")
+	params.Set("limit", strconv.Itoa(perPage))
+	if label != "" {
+		params.Set("labels", label)
+	}
+
+	// Gitea only filters on update time; the created window is applied below
+	now := time.Now()
+	if since, err := source.ParseWindow(c.search.UpdatedWithin, now); err == nil && !since.IsZero() {
+		params.Set("since", since.Format(time.RFC3339))
+	}
+	createdSince, _ := source.ParseWindow(c.search.CreatedWithin, now)
+
+	metadata := &types.RepoMetadata{
+		FullName:      repo.FullName,
+		Stars:         repo.StarsCount,
+		Forks:         repo.ForksCount,
+		OpenIssues:    repo.OpenIssuesCount,
+		PushedAt:      repo.UpdatedAt,
+		Archived:      repo.Archived,
+		DefaultBranch: repo.DefaultBranch,
+	}
+
+	candidates := []types.CandidateIssue{}
+
+	for page := 1; page <= max(c.search.MaxPages, 1); page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var issues []issue
+		if _, err := c.getJSON(ctx, fmt.Sprintf("/repos/%s/issues?%s", repo.FullName, params.Encode()), &issues); err != nil {
+			return candidates, err
+		}
+
+		for _, iss := range issues {
+			if iss.CreatedAt.Before(createdSince) || !c.withinBounds(iss) {
+				continue
+			}
+			candidates = append(candidates, c.toCandidate(iss, metadata))
+		}
+
+		if len(candidates) >= maxResults {
+			return candidates[:maxResults], nil
+		}
+
+		if len(issues) < perPage {
+			break
+		}
+	}
+
+	return candidates, nil
+}
+
+// withinBounds applies the assignee and comment bounds Gitea can't filter on
+func (c *Client) withinBounds(iss issue) bool {
+	if !c.search.IncludeAssigned && len(iss.Assignees) > 0 {
+		return false
+	}
+	if c.search.MinComments > 0 && iss.Comments < c.search.MinComments {
+		return false
+	}
+	if c.search.MaxComments > 0 && iss.Comments > c.search.MaxComments {
+		return false
+	}
+	return true
+}
+
+func (c *Client) toCandidate(iss issue, metadata *types.RepoMetadata) types.CandidateIssue {
+	labels := make([]string, len(iss.Labels))
+	for i, l := range iss.Labels {
+		labels[i] = l.Name
+	}
+
+	return types.CandidateIssue{
+		Source:     c.name,
+		Repo:       metadata.FullName,
+		Number:     iss.Number,
+		Title:      iss.Title,
+		URL:        iss.HTMLURL,
+		Labels:     labels,
+		Body:       issuebody.Prepare(iss.Body, c.search.BodyMaxChars),
+		CreatedAt:  iss.CreatedAt,
+		UpdatedAt:  iss.UpdatedAt,
+		Comments:   iss.Comments,
+		Repository: metadata,
+	}
+}
+
+// getJSON performs a GET request against the API and decodes the response
+func (c *Client) getJSON(ctx context.Context, path string, v any) (http.Header, error) {
+	return c.api.GetJSON(ctx, c.baseURL+"/api/v1"+path, v)
+}
@@ -0,0 +1,95 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+func TestFetchRelevantIssues(t *testing.T) {
+	var rateLimited atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("Authorization = %q, want %q", got, "token secret")
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/repos/search":
+			if r.URL.Query().Get("q") != "go" || r.URL.Query().Get("topic") != "true" {
+				t.Errorf("unexpected repository search %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"ok":true,"data":[
+				{"full_name":"alice/tool","language":"Go","stars_count":40},
+				{"full_name":"bob/old","language":"Go","archived":true}
+			]}`)
+		case "/api/v1/repos/alice/tool/issues":
+			// The first listing is rate limited once and must be retried
+			if !rateLimited.Swap(true) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			if got := r.URL.Query().Get("labels"); got != "good first issue" {
+				t.Errorf("labels = %q, want %q", got, "good first issue")
+			}
+			fmt.Fprint(w, `[
+				{"number":1,"title":"Fix flag parsing","html_url":"https://codeberg.org/alice/tool/issues/1","labels":[{"name":"good first issue"}],"comments":2},
+				{"number":2,"title":"Taken","html_url":"https://codeberg.org/alice/tool/issues/2","assignees":[{"login":"carol"}]}
+			]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("", server.URL, "secret",
+		WithSearchConfig(types.SearchConfig{Labels: []string{"good first issue"}, MaxPages: 1, MaxResultsPerQuery: 50}),
+		WithRetryPolicy(1, time.Millisecond),
+	)
+
+	result, err := c.FetchRelevantIssues(context.Background(), types.UserProfile{Skills: []string{"Go"}})
+	if err != nil {
+		t.Fatalf("FetchRelevantIssues() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("FetchRelevantIssues() returned %d issues, want only the unassigned one", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Repo != "alice/tool" || issue.Number != 1 || issue.Source != c.Name() {
+		t.Errorf("FetchRelevantIssues() issue = %+v", issue)
+	}
+	if issue.Repository == nil || issue.Repository.Stars != 40 {
+		t.Errorf("FetchRelevantIssues() did not attach the repository metadata")
+	}
+}
+
+func TestFetchRelevantIssuesStopsOnAuthError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := NewClient("codeberg", server.URL, "expired")
+
+	_, err := c.FetchRelevantIssues(context.Background(), types.UserProfile{Skills: []string{"Go", "Rust"}})
+
+	var authErr *source.AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("FetchRelevantIssues() error = %v, want a source.AuthError", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("sent %d requests, want the remaining queries skipped after a 401", requests.Load())
+	}
+}
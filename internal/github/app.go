@@ -0,0 +1,181 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// appJWTLifetime stays under GitHub's ten minute maximum
+	appJWTLifetime = 9 * time.Minute
+	// appClockSkew backdates the JWT in case our clock runs ahead of GitHub's
+	appClockSkew = 60 * time.Second
+	// appTokenRefreshMargin renews installation tokens this long before they
+	// expire so in-flight requests never carry an expired token
+	appTokenRefreshMargin = 5 * time.Minute
+)
+
+// AppAuth authenticates as a GitHub App installation. It signs a JWT with
+// the app's private key, exchanges it for an installation token and renews
+// the token shortly before it expires.
+type AppAuth struct {
+	appID          string
+	installationID int64
+	key            *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewAppAuth parses the app's PEM encoded private key (PKCS#1 or PKCS#8)
+func NewAppAuth(appID string, installationID int64, privateKeyPEM []byte) (*AppAuth, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("GitHub App ID is required")
+	}
+	if installationID <= 0 {
+		return nil, fmt.Errorf("GitHub App installation ID is required")
+	}
+
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+
+	var key *rsa.PrivateKey
+	if pkcs1, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = pkcs1
+	} else {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing GitHub App private key: %w", err)
+		}
+
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("GitHub App private key is not an RSA key")
+		}
+		key = rsaKey
+	}
+
+	return &AppAuth{
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+	}, nil
+}
+
+// WithAppAuth authenticates requests with installation tokens of a GitHub
+// App instead of the token passed to NewClient. Installation tokens carry
+// their own, organization-scoped rate limits.
+func WithAppAuth(auth *AppAuth) Option {
+	return func(gc *Client) {
+		if auth != nil {
+			gc.tokens = newTokenPool(nil)
+			gc.tokens.tokens = append(gc.tokens.tokens, &pooledToken{
+				name:    "github-app:" + auth.appID,
+				app:     auth,
+				limiter: newRateLimiter(),
+			})
+		}
+	}
+}
+
+// signJWT creates the RS256 signed JWT that authenticates as the app itself
+func (a *AppAuth) signJWT(now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	claims := map[string]any{
+		"iat": now.Add(-appClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": a.appID,
+	}
+
+	encode := func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	}
+
+	encodedHeader, err := encode(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encode(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodedHeader + "." + encodedClaims
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing GitHub App JWT: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// installationToken returns a valid installation token, exchanging a new
+// JWT for one when there is none yet or it is about to expire. Concurrent
+// callers share a single exchange.
+func (gc *Client) installationToken(ctx context.Context, a *AppAuth) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Until(a.expiresAt) > appTokenRefreshMargin {
+		return a.token, nil
+	}
+
+	jwt, err := a.signJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	tokenURL := gc.endpoint("/app/installations/" + strconv.FormatInt(a.installationID, 10) + "/access_tokens")
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := gc.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting GitHub App installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		if err := checkResponse(resp); err != nil {
+			return "", fmt.Errorf("error requesting GitHub App installation token: %w", err)
+		}
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", &DecodeError{Err: err}
+	}
+
+	a.token = result.Token
+	a.expiresAt = result.ExpiresAt
+	return a.token, nil
+}
@@ -0,0 +1,270 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultCacheTTL = 24 * time.Hour
+
+// cachedHeaders are the response headers needed to replay a cached response
+var cachedHeaders = []string{"Content-Type", "Link"}
+
+// httpCache stores GET responses on disk keyed by request URL and
+// revalidates them with conditional requests. GitHub does not count 304
+// responses against the rate limit.
+type httpCache struct {
+	dir string
+	ttl time.Duration
+}
+
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// DefaultCacheDir returns the cache location used when none is configured
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "issue-finder", "http")
+}
+
+// WithCache enables the on-disk HTTP cache in dir. Entries older than ttl
+// are discarded instead of being revalidated, and are deleted from dir when
+// the cache is set up.
+func WithCache(dir string, ttl time.Duration) Option {
+	return func(gc *Client) {
+		if dir == "" {
+			return
+		}
+		if ttl <= 0 {
+			ttl = defaultCacheTTL
+		}
+		gc.cache = &httpCache{dir: dir, ttl: ttl}
+		gc.cache.prune()
+	}
+}
+
+// prune deletes expired entries and temporary files left by interrupted
+// writes. Entries are written once, so their modification time is when they
+// were stored.
+func (c *httpCache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+
+		// Temporary files of a concurrent run may still be in use
+		expired := time.Since(info.ModTime()) > c.ttl
+		stale := strings.HasSuffix(entry.Name(), ".tmp") && time.Since(info.ModTime()) > time.Hour
+
+		if strings.HasSuffix(entry.Name(), ".json") && expired || stale {
+			os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+}
+
+// ClearCache removes every cached response in dir, along with temporary
+// files left by interrupted writes, and returns how many responses were
+// removed
+func ClearCache(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading cache directory: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		isEntry := strings.HasSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !isEntry && !strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("error removing cache entry: %w", err)
+		}
+		if isEntry {
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+func (c *httpCache) path(requestURL string) string {
+	sum := sha256.Sum256([]byte(requestURL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the stored entry for a URL, or nil if there is no usable one.
+// Expired and unreadable entries are deleted; search URLs contain dates, so
+// most entries are never requested again after they expire.
+func (c *httpCache) load(requestURL string) *cacheEntry {
+	path := c.path(requestURL)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != requestURL {
+		os.Remove(path)
+		return nil
+	}
+
+	if time.Since(entry.StoredAt) > c.ttl {
+		os.Remove(path)
+		return nil
+	}
+
+	return &entry
+}
+
+// store saves a successful response if it carries a validator, replacing
+// resp.Body so the caller can still read it. Only failing to read the body is
+// an error; a cache that can't be written is logged and skipped.
+func (c *httpCache) store(requestURL string, resp *http.Response) error {
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := cacheEntry{
+		URL:          requestURL,
+		ETag:         etag,
+		LastModified: lastModified,
+		StoredAt:     time.Now(),
+		Header:       http.Header{},
+		Body:         body,
+	}
+	for _, name := range cachedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			entry.Header.Set(name, value)
+		}
+	}
+
+	if err := c.write(requestURL, entry); err != nil {
+		log.Printf("Error writing HTTP cache entry: %v", err)
+	}
+
+	return nil
+}
+
+func (c *httpCache) write(requestURL string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so concurrent workers never read a
+	// partially written entry
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(requestURL))
+}
+
+// response rebuilds an HTTP response from a cached entry
+func (entry *cacheEntry) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := entry.Header.Clone()
+	for name, values := range notModified.Header {
+		if strings.HasPrefix(name, "X-Ratelimit") {
+			header[name] = values
+		}
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+// doCached sends a GET request through the cache: stored responses are
+// revalidated with If-None-Match/If-Modified-Since and served on 304
+func (gc *Client) doCached(req *http.Request) (*http.Response, error) {
+	requestURL := req.URL.String()
+
+	entry := gc.cache.load(requestURL)
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := gc.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		return entry.response(req, resp), nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if err := gc.cache.store(requestURL, resp); err != nil {
+			return nil, fmt.Errorf("error reading response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<https://example.com/next>; rel="next"`)
+		fmt.Fprintf(w, `{"items":[%s]}`, searchItem(1))
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL), WithCache(t.TempDir(), time.Hour))
+	pageURL := server.URL + "/search/issues?q=is%3Aissue"
+
+	for i := 0; i < 2; i++ {
+		items, _, next, err := gc.searchPage(context.Background(), pageURL)
+		if err != nil {
+			t.Fatalf("searchPage() #%d error = %v", i+1, err)
+		}
+		if len(items) != 1 || items[0].Number != 1 {
+			t.Errorf("searchPage() #%d = %+v, want issue 1", i+1, items)
+		}
+		if next != "https://example.com/next" {
+			t.Errorf("searchPage() #%d next = %q, want the cached Link target", i+1, next)
+		}
+	}
+
+	if requests.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("got %d requests and %d revalidations, want the second request answered with 304", requests.Load(), notModified.Load())
+	}
+}
+
+func TestCacheDeletesExpiredEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache := &httpCache{dir: dir, ttl: time.Hour}
+
+	stale := cacheEntry{URL: "https://api.github.com/old", StoredAt: time.Now().Add(-2 * time.Hour)}
+	if err := cache.write(stale.URL, stale); err != nil {
+		t.Fatal(err)
+	}
+	if cache.load(stale.URL) != nil {
+		t.Error("load() returned an expired entry")
+	}
+	if _, err := os.Stat(cache.path(stale.URL)); !os.IsNotExist(err) {
+		t.Error("load() left the expired entry on disk")
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"expired.json", "entry-1.tmp"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, old, old)
+	}
+	fresh := cacheEntry{URL: "https://api.github.com/new", StoredAt: time.Now()}
+	if err := cache.write(fresh.URL, fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	WithCache(dir, time.Hour)(&Client{})
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || cache.load(fresh.URL) == nil {
+		t.Errorf("WithCache() left %d files, want only the fresh entry", len(entries))
+	}
+}
+
+func TestClearCacheRemovesTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "entry-1.tmp"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := ClearCache(dir)
+	if err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if removed != 2 || len(entries) != 0 {
+		t.Errorf("ClearCache() removed %d entries and left %d files, want 2 removed and none left", removed, len(entries))
+	}
+}
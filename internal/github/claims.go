@@ -0,0 +1,272 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+const (
+	// ClaimedDrop removes claimed issues before evaluation
+	ClaimedDrop = "drop"
+	// ClaimedFlag keeps claimed issues but marks them for the evaluator
+	ClaimedFlag = "flag"
+	// ClaimedKeep disables claim detection
+	ClaimedKeep = "keep"
+
+	recentCommentCount = 5
+)
+
+// firstPersonClaimPattern matches comments in which someone says they are
+// taking an issue. Both straight and curly apostrophes are accepted, and
+// phrases like "I'll take a look" are not claims.
+var firstPersonClaimPattern = regexp.MustCompile(`(?i)(` +
+	`\bi(['’]?d| would|['’]?m| am) (like|love|happy|glad|keen|interested|willing) to (work on|take (this|it|on)|pick up|tackle|fix|contribute)|` +
+	`\bi(['’]?ll| will) (work on|take|pick up|tackle|handle) (this|it)\b|` +
+	`\bi(['’]?m| am) (currently |already )?working on (this|it))`)
+
+// claimRequestPattern matches comments asking for an issue to be assigned.
+// "Can I take this?" is usually a question about the issue, not a claim.
+var claimRequestPattern = regexp.MustCompile(`(?i)(` +
+	`\b(can|could|may) i (work on|pick up|tackle|be assigned|get assigned)|` +
+	`\bplease assign (this |it )?(to )?me|` +
+	`\bassign (this |it )?to me|` +
+	`^\s*/(assign|claim|take)\b)`)
+
+// maintainerAssociations are the author associations of people who run the
+// repository. Their assignment requests and commands usually hand the issue
+// to someone else, so only first-person claims count.
+var maintainerAssociations = map[string]bool{
+	"OWNER":        true,
+	"MEMBER":       true,
+	"COLLABORATOR": true,
+}
+
+type restComment struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	AuthorAssociation string    `json:"author_association"`
+	Body              string    `json:"body"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type restTimelineEvent struct {
+	Event  string `json:"event"`
+	Source *struct {
+		Issue *struct {
+			Number      int    `json:"number"`
+			HTMLURL     string `json:"html_url"`
+			State       string `json:"state"`
+			PullRequest *struct {
+				MergedAt *time.Time `json:"merged_at"`
+			} `json:"pull_request"`
+		} `json:"issue"`
+	} `json:"source"`
+}
+
+// DetectClaims looks for issues that someone has already taken, either by
+// saying so in a comment or by opening a pull request that references the
+// issue. Depending on the configured mode claimed issues are dropped or
+// flagged via CandidateIssue.Claimed. Comments and the timeline are fetched
+// per issue unless the search already loaded them, as the GraphQL backend
+// does, so callers should pass only issues that are about to be evaluated.
+// Issues whose activity could not be fetched are kept and the errors are
+// returned in a *source.FetchError.
+func (gc *Client) DetectClaims(ctx context.Context, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error) {
+	if gc.filters.Claimed == ClaimedKeep {
+		return issues, 0, nil
+	}
+
+	// Repository scans list issues over REST even with the GraphQL backend
+	errs := make([]error, len(issues))
+	runConcurrently(gc.concurrency, len(issues), func(i int) {
+		if !issues[i].ActivityLoaded {
+			errs[i] = gc.fetchIssueActivity(ctx, &issues[i])
+		}
+	})
+
+	kept := []types.CandidateIssue{}
+	claimed := 0
+	fetchErr := &source.FetchError{Source: gc.Name()}
+
+	for i, issue := range issues {
+		if errs[i] != nil {
+			query := fmt.Sprintf("issue:%s#%d", issue.Repo, issue.Number)
+			fetchErr.Errors = append(fetchErr.Errors, &source.QueryError{Query: query, Err: errs[i]})
+		}
+
+		reason := claimReason(issue)
+		if reason == "" {
+			kept = append(kept, issue)
+			continue
+		}
+
+		claimed++
+		if gc.filters.Claimed == ClaimedFlag {
+			issue.Claimed = reason
+			kept = append(kept, issue)
+		}
+	}
+
+	if len(fetchErr.Errors) > 0 {
+		return kept, claimed, fetchErr
+	}
+	return kept, claimed, nil
+}
+
+// fetchIssueActivity fills in the recent comments and linked pull requests
+// of an issue found through the REST search or issue listing APIs
+func (gc *Client) fetchIssueActivity(ctx context.Context, issue *types.CandidateIssue) error {
+	issuePath := fmt.Sprintf("/repos/%s/issues/%d", issue.Repo, issue.Number)
+
+	if issue.Comments > 0 {
+		// Comments are returned oldest first, so ask for the last page, and
+		// the one before it when the last page is too short
+		lastPage := (issue.Comments + maxPerPage - 1) / maxPerPage
+		commentsURL := gc.endpoint(issuePath + "/comments")
+
+		var comments []restComment
+		if err := gc.getJSON(ctx, fmt.Sprintf("%s?per_page=%d&page=%d", commentsURL, maxPerPage, lastPage), &comments); err != nil {
+			return err
+		}
+
+		if len(comments) < recentCommentCount && lastPage > 1 {
+			var earlier []restComment
+			if err := gc.getJSON(ctx, fmt.Sprintf("%s?per_page=%d&page=%d", commentsURL, maxPerPage, lastPage-1), &earlier); err != nil {
+				return err
+			}
+			comments = append(earlier, comments...)
+		}
+
+		if len(comments) > recentCommentCount {
+			comments = comments[len(comments)-recentCommentCount:]
+		}
+
+		issue.RecentComments = []types.IssueComment{}
+		for _, comment := range comments {
+			body := issuebody.Truncate(comment.Body, commentBodyLimit)
+
+			issue.RecentComments = append(issue.RecentComments, types.IssueComment{
+				Author:            comment.User.Login,
+				AuthorAssociation: comment.AuthorAssociation,
+				Body:              body,
+				CreatedAt:         comment.CreatedAt,
+			})
+		}
+	}
+
+	// The timeline is returned oldest first and its length isn't known up
+	// front, so follow the first page to the last one, where recently
+	// linked pull requests are
+	timelineURL := fmt.Sprintf("%s?per_page=%d", gc.endpoint(issuePath+"/timeline"), maxPerPage)
+
+	var events []restTimelineEvent
+	linkHeader, err := gc.getJSONPage(ctx, timelineURL, &events)
+	if err != nil {
+		return err
+	}
+
+	if lastURL := linkURL(linkHeader, "last"); lastURL != "" {
+		var lastEvents []restTimelineEvent
+		if _, err := gc.getJSONPage(ctx, lastURL, &lastEvents); err != nil {
+			return err
+		}
+		events = append(events, lastEvents...)
+	}
+
+	issue.LinkedPullRequests = []types.LinkedPullRequest{}
+	seenPRs := make(map[string]bool)
+	for _, event := range events {
+		if event.Event != "cross-referenced" || event.Source == nil || event.Source.Issue == nil {
+			continue
+		}
+
+		source := event.Source.Issue
+		if source.PullRequest == nil {
+			continue
+		}
+
+		state := source.State
+		if source.PullRequest.MergedAt != nil {
+			state = "merged"
+		}
+
+		if seenPRs[source.HTMLURL] {
+			continue
+		}
+		seenPRs[source.HTMLURL] = true
+
+		issue.LinkedPullRequests = append(issue.LinkedPullRequests, types.LinkedPullRequest{
+			Number: source.Number,
+			URL:    source.HTMLURL,
+			State:  state,
+		})
+	}
+
+	issue.ActivityLoaded = true
+	return nil
+}
+
+// claimReason explains why an issue looks claimed, or returns "" if it does not
+func claimReason(issue types.CandidateIssue) string {
+	for _, pr := range issue.LinkedPullRequests {
+		if pr.State == "open" {
+			return fmt.Sprintf("open pull request %s", pr.URL)
+		}
+	}
+
+	for i := len(issue.RecentComments) - 1; i >= 0; i-- {
+		comment := issue.RecentComments[i]
+		maintainer := maintainerAssociations[comment.AuthorAssociation]
+
+		for _, line := range strings.Split(comment.Body, "\n") {
+			if firstPersonClaimPattern.MatchString(line) || !maintainer && claimRequestPattern.MatchString(line) {
+				return fmt.Sprintf("claimed in a comment by @%s", comment.Author)
+			}
+		}
+	}
+
+	return ""
+}
+
+// getJSON performs an authenticated GET request and decodes the response
+func (gc *Client) getJSON(ctx context.Context, requestURL string, v any) error {
+	_, err := gc.getJSONPage(ctx, requestURL, v)
+	return err
+}
+
+// getJSONPage is getJSON for paginated endpoints; it also returns the Link
+// header of the response
+func (gc *Client) getJSONPage(ctx context.Context, requestURL string, v any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := gc.do(req)
+	if err != nil {
+		return "", fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", &DecodeError{Err: err}
+	}
+
+	return resp.Header.Get("Link"), nil
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+func TestClaimReason(t *testing.T) {
+	tests := []struct {
+		body        string
+		association string
+		claimed     bool
+	}{
+		{body: "I'd like to work on this", claimed: true},
+		{body: "Thanks, I’d like to work on this", claimed: true},
+		{body: "I'll take this one", claimed: true},
+		{body: "I’ll work on it over the weekend", claimed: true},
+		{body: "I'm currently working on this", claimed: true},
+		{body: "Could I work on this?", claimed: true},
+		{body: "Please assign this to me", claimed: true},
+		{body: "/assign", claimed: true},
+		{body: "I'll take a look at this tomorrow", claimed: false},
+		{body: "Can I take this?", claimed: false},
+		{body: "I'd like to take a look first", claimed: false},
+		{body: "This breaks on Windows too", claimed: false},
+		{body: "I'm currently working on this", association: "MEMBER", claimed: true},
+		{body: "Could I work on this?", association: "COLLABORATOR", claimed: false},
+		{body: "/assign", association: "OWNER", claimed: false},
+		{body: "I'll work on this", association: "CONTRIBUTOR", claimed: true},
+	}
+
+	for _, tt := range tests {
+		issue := types.CandidateIssue{
+			RecentComments: []types.IssueComment{
+				{Author: "someone", AuthorAssociation: tt.association, Body: tt.body},
+			},
+		}
+
+		reason := claimReason(issue)
+		if (reason != "") != tt.claimed {
+			t.Errorf("claimReason(%q, %s) = %q, want claimed=%v", tt.body, tt.association, reason, tt.claimed)
+		}
+	}
+}
+
+func TestClaimReasonOpenPullRequest(t *testing.T) {
+	issue := types.CandidateIssue{
+		LinkedPullRequests: []types.LinkedPullRequest{
+			{Number: 1, URL: "https://github.com/o/r/pull/1", State: "merged"},
+			{Number: 2, URL: "https://github.com/o/r/pull/2", State: "open"},
+		},
+	}
+
+	if reason := claimReason(issue); reason != "open pull request https://github.com/o/r/pull/2" {
+		t.Errorf("claimReason() = %q", reason)
+	}
+}
+
+func TestDetectClaimsReadsLastTimelinePage(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/o/r/issues/1/timeline" && r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/o/r/issues/1/timeline?page=2>; rel="next", <%s/repos/o/r/issues/1/timeline?page=3>; rel="last"`, server.URL, server.URL))
+			fmt.Fprint(w, `[{"event":"labeled"}]`)
+		case r.URL.Path == "/repos/o/r/issues/1/timeline" && r.URL.Query().Get("page") == "3":
+			fmt.Fprint(w, `[{"event":"cross-referenced","source":{"issue":{"number":7,"html_url":"https://github.com/o/r/pull/7","state":"open","pull_request":{}}}}]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL))
+	issues := []types.CandidateIssue{{Repo: "o/r", Number: 1, URL: "https://github.com/o/r/issues/1"}}
+
+	kept, claimed, err := gc.DetectClaims(context.Background(), issues)
+	if err != nil {
+		t.Fatalf("DetectClaims() error = %v", err)
+	}
+	if claimed != 1 || len(kept) != 0 {
+		t.Errorf("DetectClaims() kept %d, claimed %d; want the issue dropped as claimed", len(kept), claimed)
+	}
+}
+
+func TestDetectClaimsReadsPageBeforeShortLastPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/o/r/issues/1/comments" && r.URL.Query().Get("page") == "2":
+			fmt.Fprint(w, `[{"user":{"login":"bot"},"body":"This issue is stale"}]`)
+		case r.URL.Path == "/repos/o/r/issues/1/comments" && r.URL.Query().Get("page") == "1":
+			comments := make([]string, 100)
+			for i := range comments {
+				comments[i] = `{"user":{"login":"someone"},"body":"+1"}`
+			}
+			comments[98] = `{"user":{"login":"alice"},"body":"I'll take this"}`
+			fmt.Fprintf(w, "[%s]", strings.Join(comments, ","))
+		case r.URL.Path == "/repos/o/r/issues/1/timeline":
+			fmt.Fprint(w, `[]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL))
+	issues := []types.CandidateIssue{{Repo: "o/r", Number: 1, Comments: 101, URL: "https://github.com/o/r/issues/1"}}
+
+	kept, claimed, err := gc.DetectClaims(context.Background(), issues)
+	if err != nil {
+		t.Fatalf("DetectClaims() error = %v", err)
+	}
+	if claimed != 1 || len(kept) != 0 {
+		t.Errorf("DetectClaims() kept %d, claimed %d; want the claim on the previous page found", len(kept), claimed)
+	}
+}
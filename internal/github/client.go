@@ -1,45 +1,239 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/internal/skillmap"
+	"github.com/ashishra0/issue-finder/internal/source"
 	"github.com/ashishra0/issue-finder/pkg/types"
 )
 
+// DefaultBaseURL is the REST API root for github.com
+const DefaultBaseURL = "https://api.github.com"
+
+const (
+	// searchResultCap is the maximum number of results GitHub search returns for a query
+	searchResultCap = 1000
+	maxPerPage      = 100
+
+	defaultMaxPages           = 3
+	defaultMaxResultsPerQuery = 300
+)
+
 type Client struct {
-	token      string
+	tokens     *tokenPool
+	baseURL    string
+	backend    string
 	httpClient *http.Client
+	cache      *httpCache
+	search     types.SearchConfig
+
+	maxRetries  int
+	baseBackoff time.Duration
+
+	concurrency int
+	skillMap    *skillmap.Map
+
+	filters    types.FiltersConfig
+	watchRepos []string
+	repoMu     sync.Mutex
+	repoCache  map[string]*types.RepoMetadata
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL routes all requests through the given API root, e.g.
+// https://github.example.com/api/v3 for GitHub Enterprise Server or the
+// URL of a local test server
+func WithBaseURL(baseURL string) Option {
+	return func(gc *Client) {
+		if baseURL != "" {
+			gc.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithBackend selects BackendREST or BackendGraphQL for searches
+func WithBackend(backend string) Option {
+	return func(gc *Client) {
+		if backend != "" {
+			gc.backend = backend
+		}
+	}
 }
 
-func NewClient(token string) *Client {
-	return &Client{
-		token: token,
+// WithSearchConfig sets how search queries are built and paginated. Start
+// from DefaultSearchConfig to keep the defaults; zero pagination limits fall
+// back to them.
+func WithSearchConfig(cfg types.SearchConfig) Option {
+	return func(gc *Client) {
+		if cfg.MaxPages <= 0 {
+			cfg.MaxPages = defaultMaxPages
+		}
+		if cfg.MaxResultsPerQuery <= 0 {
+			cfg.MaxResultsPerQuery = defaultMaxResultsPerQuery
+		}
+		cfg.MaxResultsPerQuery = min(cfg.MaxResultsPerQuery, searchResultCap)
+
+		gc.search = cfg
+	}
+}
+
+// WithRetryPolicy sets how many times a rate limited request is retried and
+// the initial backoff used for secondary rate limits without Retry-After
+func WithRetryPolicy(maxRetries int, baseBackoff time.Duration) Option {
+	return func(gc *Client) {
+		if maxRetries > 0 {
+			gc.maxRetries = maxRetries
+		}
+		if baseBackoff > 0 {
+			gc.baseBackoff = baseBackoff
+		}
+	}
+}
+
+// WithConcurrency sets how many queries run at the same time. All workers
+// share the client's token pool and its rate limits.
+func WithConcurrency(workers int) Option {
+	return func(gc *Client) {
+		if workers > 0 {
+			gc.concurrency = workers
+		}
+	}
+}
+
+// WithSkillMap sets how profile skills translate into search qualifiers
+func WithSkillMap(skillMap *skillmap.Map) Option {
+	return func(gc *Client) {
+		if skillMap != nil {
+			gc.skillMap = skillMap
+		}
+	}
+}
+
+// WithFilters sets the thresholds used to drop issues from unhealthy
+// repositories before they are returned
+func WithFilters(cfg types.FiltersConfig) Option {
+	return func(gc *Client) {
+		gc.filters = cfg
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(gc *Client) {
+		if httpClient != nil {
+			gc.httpClient = httpClient
+		}
+	}
+}
+
+func NewClient(token string, opts ...Option) *Client {
+	gc := &Client{
+		tokens:  newTokenPool([]Token{{Name: "token", Value: token}}),
+		baseURL: DefaultBaseURL,
+		backend: BackendREST,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		search:      DefaultSearchConfig(),
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBaseBackoff,
+		concurrency: defaultConcurrency,
+		skillMap:    skillmap.Default(),
+		repoCache:   make(map[string]*types.RepoMetadata),
+	}
+
+	for _, opt := range opts {
+		opt(gc)
+	}
+
+	return gc
+}
+
+// Name identifies the client as an issue source: "github" for github.com
+// and the host name for GitHub Enterprise Server
+func (gc *Client) Name() string {
+	if gc.baseURL == DefaultBaseURL {
+		return types.DefaultSource
 	}
+
+	if parsed, err := url.Parse(gc.baseURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return gc.baseURL
+}
+
+// endpoint joins an API path such as "/search/issues" onto the base URL
+func (gc *Client) endpoint(path string) string {
+	return gc.baseURL + "/" + strings.TrimLeft(path, "/")
 }
 
-// FetchRelevantIssues fetches issues based on profile - multiple targeted queries
-func (gc *Client) FetchRelevantIssues(profile types.UserProfile) []map[string]any {
+// FetchRelevantIssues fetches issues based on profile - multiple targeted queries.
+// Queries run concurrently on a bounded worker pool and stop when ctx is
+// cancelled. Failed queries are reported in a *source.FetchError while issues from
+// the other queries are still returned.
+func (gc *Client) FetchRelevantIssues(ctx context.Context, profile types.UserProfile) (source.Result, error) {
+	if len(gc.watchRepos) > 0 {
+		return gc.scanRepositories(ctx)
+	}
+
 	queries := gc.buildSearchQueries(profile)
 
-	allIssues := []map[string]any{}
+	type queryResult struct {
+		issues     []types.CandidateIssue
+		incomplete bool
+		err        error
+	}
+
+	// An auth failure means every other query will fail as well
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var authFailed atomic.Bool
+
+	results := make([]queryResult, len(queries))
+	runConcurrently(gc.concurrency, len(queries), func(i int) {
+		issues, incomplete, err := gc.runQuery(ctx, queries[i])
+		if isAuthError(err) {
+			authFailed.Store(true)
+			cancel()
+		}
+		results[i] = queryResult{issues: issues, incomplete: incomplete, err: err}
+	})
+
+	result := source.Result{Queries: len(queries)}
+	allIssues := []types.CandidateIssue{}
 	seenIssueURLs := make(map[string]bool)
+	fetchErr := &source.FetchError{Source: gc.Name()}
 
 	for i, query := range queries {
-		if i > 0 {
-			time.Sleep(2 * time.Second)
+		queryResult := results[i]
+		if queryResult.incomplete {
+			result.Incomplete = append(result.Incomplete, query.String())
 		}
+		if queryResult.err != nil && !(authFailed.Load() && errors.Is(queryResult.err, context.Canceled)) {
+			fetchErr.Errors = append(fetchErr.Errors, &source.QueryError{Query: query.String(), Err: queryResult.err})
+		}
+	}
 
-		issues := gc.searchIssues(query)
+	issueLists := make([][]types.CandidateIssue, len(results))
+	for i := range results {
+		issueLists[i] = results[i].issues
+	}
 
+	for _, issues := range mixResults(queries, issueLists) {
 		for _, issue := range issues {
 			issueURL := issue.URL
 
@@ -49,164 +243,179 @@ func (gc *Client) FetchRelevantIssues(profile types.UserProfile) []map[string]an
 
 			seenIssueURLs[issueURL] = true
 
-			labelNames := []string{}
-			for _, label := range issue.Labels {
-				labelNames = append(labelNames, label.Name)
-			}
-
-			repoName := gc.extractRepoName(issue.RepoURL)
-
-			body := issue.Body
-			if len(body) > 500 {
-				body = body[:500] + "... [truncated]"
-			}
-
-			allIssues = append(allIssues, map[string]any{
-				"repo":       repoName,
-				"number":     issue.Number,
-				"title":      issue.Title,
-				"url":        issue.URL,
-				"labels":     labelNames,
-				"body":       body,
-				"created_at": issue.CreatedAt.Format("2006-01-02"),
-			})
+			issue.Source = gc.Name()
+			allIssues = append(allIssues, issue)
 		}
 	}
 
-	return allIssues
+	result.Issues = allIssues
+
+	if len(fetchErr.Errors) > 0 {
+		return result, fetchErr
+	}
+	return result, nil
 }
 
-// buildSearchQueries builds targeted search queries based on skills and interests
-func (gc *Client) buildSearchQueries(profile types.UserProfile) []string {
-	queries := []string{}
+// mixResults interleaves the results of queries in the same group, so the
+// top results of every ordering of a mixed query come before the tail of
+// any one ordering. Groups keep their query order.
+func mixResults(queries []searchQuery, results [][]types.CandidateIssue) [][]types.CandidateIssue {
+	mixed := [][]types.CandidateIssue{}
 
-	baseConstraints := "is:issue is:open no:assignee created:>2024-10-01 comments:>=1"
-	goodLabels := []string{"good first issue", "help wanted"}
+	for start := 0; start < len(queries); {
+		end := start + 1
+		for end < len(queries) && queries[end].group == queries[start].group {
+			end++
+		}
 
-	for _, skill := range profile.Skills {
-		skillLower := strings.ToLower(skill)
-		languageOrTopic := gc.mapSkillToGitHub(skillLower)
+		if end-start == 1 {
+			mixed = append(mixed, results[start])
+			start = end
+			continue
+		}
 
-		for _, label := range goodLabels {
-			query := fmt.Sprintf("%s %s label:\"%s\"", baseConstraints, languageOrTopic, label)
-			queries = append(queries, query)
+		merged := []types.CandidateIssue{}
+		for rank := 0; ; rank++ {
+			added := false
+			for i := start; i < end; i++ {
+				if rank < len(results[i]) {
+					merged = append(merged, results[i][rank])
+					added = true
+				}
+			}
+			if !added {
+				break
+			}
 		}
+		mixed = append(mixed, merged)
+		start = end
 	}
 
-	return queries
+	return mixed
 }
 
-func (gc *Client) mapSkillToGitHub(skill string) string {
-	mappings := map[string]string{
-		"python":         "language:python",
-		"ruby on rails":  "language:ruby topic:rails",
-		"ruby":           "language:ruby",
-		"go":             "language:go",
-		"golang":         "language:go",
-		"rust":           "language:rust",
-		"javascript":     "language:javascript",
-		"typescript":     "language:typescript",
-		"java":           "language:java",
-		"c++":            "language:c++",
-		"c":              "language:c",
-		"php":            "language:php",
-		"swift":          "language:swift",
-		"kotlin":         "language:kotlin",
-		"postgresql":     "topic:postgresql",
-		"postgres":       "topic:postgresql",
-		"sqlite":         "topic:sqlite",
-		"mysql":          "topic:mysql",
-		"mongodb":        "topic:mongodb",
-		"redis":          "topic:redis",
-		"message queues": "topic:message-queue OR topic:rabbitmq OR topic:kafka",
-		"event-driven":   "topic:event-driven",
-	}
-
-	mapped := mappings[skill]
-	if mapped != "" {
-		return mapped
+// runQuery executes a search query with the configured backend
+func (gc *Client) runQuery(ctx context.Context, query searchQuery) ([]types.CandidateIssue, bool, error) {
+	if gc.backend == BackendGraphQL {
+		return gc.searchIssuesGraphQL(ctx, query)
 	}
-
-	return fmt.Sprintf("topic:%s", skill)
+	return gc.searchIssues(ctx, query)
 }
 
-func (gc *Client) searchIssues(query string) []types.GitHubIssue {
-	searchURL := "https://api.github.com/search/issues?q="
+// searchIssues runs a search query, following pagination until the
+// configured page or result limit is reached. The second return value
+// reports whether GitHub flagged any page as incomplete. Pages fetched
+// before an error are returned along with it.
+func (gc *Client) searchIssues(ctx context.Context, query searchQuery) ([]types.CandidateIssue, bool, error) {
+	maxResults := gc.search.MaxResultsPerQuery
 
 	params := url.Values{}
-	params.Add("q", query)
-	params.Add("sort", "created")
+	params.Add("q", query.q)
+	params.Add("sort", query.sort)
 	params.Add("order", "desc")
-	params.Add("per_page", "30")
+	params.Add("per_page", strconv.Itoa(min(maxResults, maxPerPage)))
+
+	pageURL := fmt.Sprintf("%s?%s", gc.endpoint("/search/issues"), params.Encode())
 
-	fullURL := fmt.Sprintf("%s?%s", searchURL, params.Encode())
+	allItems := []types.CandidateIssue{}
+	incomplete := false
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	for page := 0; page < gc.search.MaxPages && pageURL != ""; page++ {
+		items, pageIncomplete, nextURL, err := gc.searchPage(ctx, pageURL)
+		if err != nil {
+			return allItems, incomplete, err
+		}
+
+		incomplete = incomplete || pageIncomplete
+		for _, item := range items {
+			allItems = append(allItems, gc.toCandidate(item))
+		}
+		pageURL = nextURL
+
+		if len(allItems) >= maxResults {
+			allItems = allItems[:maxResults]
+			break
+		}
+	}
+
+	return allItems, incomplete, nil
+}
+
+// searchPage fetches a single page of search results and returns its items,
+// the incomplete_results flag and the URL of the next page, if any
+func (gc *Client) searchPage(ctx context.Context, pageURL string) ([]types.GitHubIssue, bool, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		return []types.GitHubIssue{}
+		return nil, false, "", fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", gc.token))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := gc.httpClient.Do(req)
+	resp, err := gc.do(req)
 	if err != nil {
-		fmt.Printf("Error executing request: %v\n", err)
-		return []types.GitHubIssue{}
+		return nil, false, "", fmt.Errorf("error executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 401 {
-		fmt.Println("GitHub authentication failed (401 Unauthorized)")
-		fmt.Println("Your GITHUB_TOKEN may be invalid or expired.")
-		fmt.Println("Create a new token at: https://github.com/settings/tokens")
-		return []types.GitHubIssue{}
-	}
-
-	if resp.StatusCode == 403 {
-		body, _ := io.ReadAll(resp.Body)
-		bodyStr := string(body)
-
-		if strings.Contains(bodyStr, "secondary rate limit") {
-			fmt.Println("Hit GitHub secondary rate limit (too many requests too quickly)")
-			fmt.Println("Please wait 5-10 minutes before trying again")
-		} else {
-			rateLimitRemaining := resp.Header.Get("X-RateLimit-Remaining")
-			if rateLimitRemaining == "0" {
-				fmt.Println("GitHub primary rate limit exceeded")
-				fmt.Printf("Resets at: %s\n", resp.Header.Get("X-RateLimit-Reset"))
-			} else {
-				fmt.Println("GitHub API access denied (403)")
-			}
-		}
-		return []types.GitHubIssue{}
+	if err := checkResponse(resp); err != nil {
+		return nil, false, "", err
 	}
 
-	if resp.StatusCode != 200 {
-		fmt.Printf("GitHub API error: %d\n", resp.StatusCode)
-		body, _ := io.ReadAll(resp.Body)
-		fmt.Printf("Response: %s\n", string(body))
-		return []types.GitHubIssue{}
+	var result struct {
+		IncompleteResults bool                `json:"incomplete_results"`
+		Items             []types.GitHubIssue `json:"items"`
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Error reading response: %v\n", err)
-		return []types.GitHubIssue{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, false, "", &DecodeError{Err: err}
 	}
 
-	var result struct {
-		Items []types.GitHubIssue `json:"items"`
+	return result.Items, result.IncompleteResults, nextPageURL(resp.Header.Get("Link")), nil
+}
+
+// nextPageURL extracts the rel="next" target from a Link header
+func nextPageURL(linkHeader string) string {
+	return linkURL(linkHeader, "next")
+}
+
+// linkURL extracts the target of the given relation from a Link header
+func linkURL(linkHeader, rel string) string {
+	for _, link := range strings.Split(linkHeader, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="`+rel+`"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
 	}
 
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		fmt.Printf("Error parsing JSON: %v\n", err)
-		return []types.GitHubIssue{}
+	return ""
+}
+
+// toCandidate converts a search result into the model used by the rest of
+// the pipeline
+func (gc *Client) toCandidate(issue types.GitHubIssue) types.CandidateIssue {
+	labelNames := []string{}
+	for _, label := range issue.Labels {
+		labelNames = append(labelNames, label.Name)
 	}
 
-	return result.Items
+	return types.CandidateIssue{
+		Repo:              gc.extractRepoName(issue.RepoURL),
+		Number:            issue.Number,
+		Title:             issue.Title,
+		URL:               issue.URL,
+		Labels:            labelNames,
+		Body:              issuebody.Prepare(issue.Body, gc.search.BodyMaxChars),
+		CreatedAt:         issue.CreatedAt,
+		UpdatedAt:         issue.UpdatedAt,
+		Comments:          issue.Comments,
+		AuthorAssociation: issue.AuthorAssociation,
+	}
 }
 
 func (gc *Client) extractRepoName(repoURL string) string {
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// searchItem renders one issue as returned by the search API
+func searchItem(number int) string {
+	return fmt.Sprintf(`{"number":%d,"title":"Issue %d","html_url":"https://github.com/o/r/issues/%d","repository_url":"https://api.github.com/repos/o/r"}`, number, number, number)
+}
+
+func TestSearchUsesBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("Authorization = %q, want %q", got, "token secret")
+		}
+		fmt.Fprintf(w, `{"items":[%s]}`, searchItem(1))
+	}))
+	defer server.Close()
+
+	gc := NewClient("secret", WithBaseURL(server.URL+"/api/v3/"))
+
+	issues, _, err := gc.searchIssues(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+	if err != nil {
+		t.Fatalf("searchIssues() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Repo != "o/r" {
+		t.Errorf("searchIssues() = %+v, want the issue from o/r", issues)
+	}
+	if len(paths) != 1 || paths[0] != "/api/v3/search/issues" {
+		t.Errorf("requested paths %v, want [/api/v3/search/issues]", paths)
+	}
+}
+
+func TestSearchFollowsLinkPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/search/issues?page=2>; rel="next", <%s/search/issues?page=2>; rel="last"`, server.URL, server.URL))
+			fmt.Fprintf(w, `{"items":[%s,%s]}`, searchItem(1), searchItem(2))
+		case "2":
+			fmt.Fprintf(w, `{"incomplete_results":true,"items":[%s]}`, searchItem(3))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL))
+
+	issues, incomplete, err := gc.searchIssues(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+	if err != nil {
+		t.Fatalf("searchIssues() error = %v", err)
+	}
+	if len(issues) != 3 || issues[2].Number != 3 {
+		t.Errorf("searchIssues() returned %d issues, want all 3 from both pages", len(issues))
+	}
+	if !incomplete {
+		t.Error("searchIssues() lost the incomplete_results flag of the second page")
+	}
+}
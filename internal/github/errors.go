@@ -0,0 +1,122 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuthError is returned when GitHub rejects the credentials (401)
+type AuthError struct {
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	msg := "GitHub authentication failed (401 Unauthorized): token may be invalid or expired"
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// RateLimitError is returned when a request is still rate limited after
+// all retries. Reset is zero when GitHub did not report a reset time.
+type RateLimitError struct {
+	Secondary bool
+	Reset     time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	kind := "primary"
+	if e.Secondary {
+		kind = "secondary"
+	}
+
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("GitHub %s rate limit exceeded", kind)
+	}
+	return fmt.Sprintf("GitHub %s rate limit exceeded, resets at %s", kind, e.Reset.Format(time.RFC3339))
+}
+
+// HTTPError is returned for any other non-200 response
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("GitHub API error %d: %s", e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// GraphQLError is returned when a GraphQL response carries errors
+type GraphQLError struct {
+	Messages []string
+}
+
+func (e *GraphQLError) Error() string {
+	return fmt.Sprintf("GitHub GraphQL error: %s", strings.Join(e.Messages, "; "))
+}
+
+// DecodeError is returned when a response body cannot be parsed
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error parsing GitHub response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// isAuthError reports whether err means every further request will fail too
+func isAuthError(err error) bool {
+	var authErr *AuthError
+	return errors.As(err, &authErr)
+}
+
+// checkResponse converts an unsuccessful response into a typed error
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &apiErr)
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return &AuthError{Message: apiErr.Message}
+
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && (remaining == "0" ||
+			resp.Header.Get("Retry-After") != "" ||
+			strings.Contains(strings.ToLower(apiErr.Message), "rate limit")):
+		return rateLimitError(resp.Header)
+
+	default:
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
+
+// rateLimitError describes a rate limit rejection from its response headers.
+// Only an exhausted quota is a primary limit; anything else is secondary.
+func rateLimitError(header http.Header) *RateLimitError {
+	rateErr := &RateLimitError{Secondary: header.Get("X-RateLimit-Remaining") != "0"}
+	if epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateErr.Reset = time.Unix(epoch, 0)
+	}
+	return rateErr
+}
@@ -0,0 +1,30 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnauthorizedIsAuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Bad credentials"}`)
+	}))
+	defer server.Close()
+
+	gc := NewClient("expired", WithBaseURL(server.URL))
+
+	_, _, err := gc.searchIssues(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.Message != "Bad credentials" {
+		t.Errorf("searchIssues() error = %v, want an AuthError", err)
+	}
+	if !isAuthError(err) {
+		t.Error("isAuthError() = false for a 401")
+	}
+}
@@ -0,0 +1,48 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/ashishra0/issue-finder/internal/source"
+)
+
+// repoQualifiers renders the allow and deny lists as search qualifiers where
+// GitHub search can express them. Glob patterns can't be, so they are only
+// applied after the search, by source.Result.ExcludeRepos.
+func (gc *Client) repoQualifiers() string {
+	parts := []string{}
+
+	for _, repo := range gc.filters.ExcludeRepos {
+		if !source.IsGlob(repo) {
+			parts = append(parts, "-repo:"+repo)
+		}
+	}
+	for _, org := range gc.filters.ExcludeOrgs {
+		if !source.IsGlob(org) {
+			parts = append(parts, "-org:"+org)
+		}
+	}
+
+	// Multiple repo: and org: qualifiers are OR'd, which matches include
+	// semantics, but only if every include entry can be expressed
+	includes := append(append([]string{}, gc.filters.IncludeRepos...), gc.filters.IncludeOrgs...)
+	if len(includes) > 0 && !anyGlob(includes) {
+		for _, repo := range gc.filters.IncludeRepos {
+			parts = append(parts, "repo:"+repo)
+		}
+		for _, org := range gc.filters.IncludeOrgs {
+			parts = append(parts, "org:"+org)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func anyGlob(patterns []string) bool {
+	for _, pattern := range patterns {
+		if source.IsGlob(pattern) {
+			return true
+		}
+	}
+	return false
+}
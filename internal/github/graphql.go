@@ -0,0 +1,308 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+const (
+	// BackendREST searches with the REST search API and fetches details with
+	// separate requests
+	BackendREST = "rest"
+	// BackendGraphQL fetches issues, repository details, recent comments and
+	// linked pull requests in a single query per page
+	BackendGraphQL = "graphql"
+
+	commentBodyLimit = 300
+)
+
+const searchIssuesQuery = `query($q: String!, $first: Int!, $after: String) {
+  search(type: ISSUE, query: $q, first: $first, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Issue {
+        number
+        title
+        url
+        body
+        createdAt
+        updatedAt
+        authorAssociation
+        labels(first: 20) { nodes { name } }
+        comments(last: 5) { totalCount nodes { author { login } authorAssociation body createdAt } }
+        timelineItems(last: 20, itemTypes: [CROSS_REFERENCED_EVENT, CONNECTED_EVENT]) {
+          nodes {
+            ... on CrossReferencedEvent { source { ... on PullRequest { number url state } } }
+            ... on ConnectedEvent { subject { ... on PullRequest { number url state } } }
+          }
+        }
+        repository {
+          nameWithOwner
+          stargazerCount
+          forkCount
+          pushedAt
+          isArchived
+          isDisabled
+          licenseInfo { spdxId }
+          defaultBranchRef { name }
+          primaryLanguage { name }
+          issues(states: OPEN) { totalCount }
+        }
+      }
+    }
+  }
+}`
+
+type graphQLPullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+}
+
+type graphQLIssue struct {
+	Number            int       `json:"number"`
+	Title             string    `json:"title"`
+	URL               string    `json:"url"`
+	Body              string    `json:"body"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+	AuthorAssociation string    `json:"authorAssociation"`
+	Labels            struct {
+		Nodes []types.Label `json:"nodes"`
+	} `json:"labels"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			Author *struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			AuthorAssociation string    `json:"authorAssociation"`
+			Body              string    `json:"body"`
+			CreatedAt         time.Time `json:"createdAt"`
+		} `json:"nodes"`
+	} `json:"comments"`
+	TimelineItems struct {
+		Nodes []struct {
+			Source  *graphQLPullRequest `json:"source"`
+			Subject *graphQLPullRequest `json:"subject"`
+		} `json:"nodes"`
+	} `json:"timelineItems"`
+	Repository struct {
+		NameWithOwner  string    `json:"nameWithOwner"`
+		StargazerCount int       `json:"stargazerCount"`
+		ForkCount      int       `json:"forkCount"`
+		PushedAt       time.Time `json:"pushedAt"`
+		IsArchived     bool      `json:"isArchived"`
+		IsDisabled     bool      `json:"isDisabled"`
+		LicenseInfo    *struct {
+			SPDXID string `json:"spdxId"`
+		} `json:"licenseInfo"`
+		DefaultBranchRef *struct {
+			Name string `json:"name"`
+		} `json:"defaultBranchRef"`
+		PrimaryLanguage *struct {
+			Name string `json:"name"`
+		} `json:"primaryLanguage"`
+		Issues struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"issues"`
+	} `json:"repository"`
+}
+
+type graphQLResponse struct {
+	Data struct {
+		Search struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []graphQLIssue `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLEndpoint derives the GraphQL URL from the REST base URL. GitHub
+// Enterprise Server serves REST at /api/v3 and GraphQL at /api/graphql.
+func (gc *Client) graphQLEndpoint() string {
+	if strings.HasSuffix(gc.baseURL, "/api/v3") {
+		return strings.TrimSuffix(gc.baseURL, "/v3") + "/graphql"
+	}
+	return gc.endpoint("/graphql")
+}
+
+// searchIssuesGraphQL runs a search query through the GraphQL API, following
+// cursors until the configured page or result limit is reached
+func (gc *Client) searchIssuesGraphQL(ctx context.Context, query searchQuery) ([]types.CandidateIssue, bool, error) {
+	maxResults := gc.search.MaxResultsPerQuery
+	variables := map[string]any{
+		"q":     fmt.Sprintf("%s sort:%s-desc", query.q, query.sort),
+		"first": min(maxResults, maxPerPage),
+	}
+
+	candidates := []types.CandidateIssue{}
+
+	for page := 0; page < gc.search.MaxPages; page++ {
+		result, err := gc.graphQLSearchPage(ctx, variables)
+		if err != nil {
+			return candidates, false, err
+		}
+
+		for _, issue := range result.Data.Search.Nodes {
+			// Non-issue nodes decode as empty structs
+			if issue.URL == "" {
+				continue
+			}
+			candidates = append(candidates, issue.toCandidate(gc.search.BodyMaxChars))
+		}
+
+		if len(candidates) >= maxResults {
+			return candidates[:maxResults], false, nil
+		}
+
+		pageInfo := result.Data.Search.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = pageInfo.EndCursor
+	}
+
+	return candidates, false, nil
+}
+
+func (gc *Client) graphQLSearchPage(ctx context.Context, variables map[string]any) (*graphQLResponse, error) {
+	payload, err := json.Marshal(map[string]any{
+		"query":     searchIssuesQuery,
+		"variables": variables,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", gc.graphQLEndpoint(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := gc.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+
+	if len(result.Errors) > 0 {
+		messages := []string{}
+		for _, gqlErr := range result.Errors {
+			if gqlErr.Type == "RATE_LIMITED" {
+				// Still limited after send's retries
+				return nil, rateLimitError(resp.Header)
+			}
+			messages = append(messages, gqlErr.Message)
+		}
+		return nil, &GraphQLError{Messages: messages}
+	}
+
+	return &result, nil
+}
+
+func (issue graphQLIssue) toCandidate(bodyMaxChars int) types.CandidateIssue {
+	labels := []string{}
+	for _, label := range issue.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+
+	comments := []types.IssueComment{}
+	for _, comment := range issue.Comments.Nodes {
+		author := "ghost"
+		if comment.Author != nil {
+			author = comment.Author.Login
+		}
+
+		body := issuebody.Truncate(comment.Body, commentBodyLimit)
+
+		comments = append(comments, types.IssueComment{
+			Author:            author,
+			AuthorAssociation: comment.AuthorAssociation,
+			Body:              body,
+			CreatedAt:         comment.CreatedAt,
+		})
+	}
+
+	pullRequests := []types.LinkedPullRequest{}
+	seenPRs := make(map[string]bool)
+	for _, item := range issue.TimelineItems.Nodes {
+		pr := item.Source
+		if pr == nil {
+			pr = item.Subject
+		}
+		if pr == nil || pr.URL == "" || seenPRs[pr.URL] {
+			continue
+		}
+
+		seenPRs[pr.URL] = true
+		pullRequests = append(pullRequests, types.LinkedPullRequest{
+			Number: pr.Number,
+			URL:    pr.URL,
+			State:  strings.ToLower(pr.State),
+		})
+	}
+
+	repo := issue.Repository
+	metadata := &types.RepoMetadata{
+		FullName:   repo.NameWithOwner,
+		Stars:      repo.StargazerCount,
+		Forks:      repo.ForkCount,
+		OpenIssues: repo.Issues.TotalCount,
+		PushedAt:   repo.PushedAt,
+		Archived:   repo.IsArchived,
+		Disabled:   repo.IsDisabled,
+	}
+	if repo.LicenseInfo != nil {
+		metadata.License = repo.LicenseInfo.SPDXID
+	}
+	if repo.DefaultBranchRef != nil {
+		metadata.DefaultBranch = repo.DefaultBranchRef.Name
+	}
+	if repo.PrimaryLanguage != nil {
+		metadata.PrimaryLanguage = repo.PrimaryLanguage.Name
+	}
+
+	return types.CandidateIssue{
+		Repo:               repo.NameWithOwner,
+		Number:             issue.Number,
+		Title:              issue.Title,
+		URL:                issue.URL,
+		Labels:             labels,
+		Body:               issuebody.Prepare(issue.Body, bodyMaxChars),
+		CreatedAt:          issue.CreatedAt,
+		UpdatedAt:          issue.UpdatedAt,
+		Comments:           issue.Comments.TotalCount,
+		AuthorAssociation:  issue.AuthorAssociation,
+		Repository:         metadata,
+		RecentComments:     comments,
+		LinkedPullRequests: pullRequests,
+		ActivityLoaded:     true,
+	}
+}
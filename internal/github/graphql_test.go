@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const graphQLRateLimited = `{"data":null,"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`
+
+func TestGraphQLRateLimitedIsRetried(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if requests.Add(1) == 1 {
+			fmt.Fprint(w, graphQLRateLimited)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"search":{"nodes":[{"number":1,"url":"https://github.com/o/r/issues/1","repository":{"nameWithOwner":"o/r"}}]}}}`)
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(2, time.Millisecond))
+
+	issues, _, err := gc.searchIssuesGraphQL(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+	if err != nil {
+		t.Fatalf("searchIssuesGraphQL() error = %v", err)
+	}
+	if len(issues) != 1 || requests.Load() != 2 {
+		t.Errorf("searchIssuesGraphQL() returned %d issues after %d requests, want 1 after a retry", len(issues), requests.Load())
+	}
+}
+
+func TestGraphQLRateLimitErrorCarriesReset(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		fmt.Fprint(w, graphQLRateLimited)
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(1, time.Millisecond))
+
+	_, _, err := gc.searchIssuesGraphQL(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("searchIssuesGraphQL() error = %v, want a RateLimitError", err)
+	}
+	if !rateErr.Reset.Equal(reset) {
+		t.Errorf("RateLimitError.Reset = %v, want %v", rateErr.Reset, reset)
+	}
+}
@@ -0,0 +1,34 @@
+package github
+
+import "sync"
+
+const defaultConcurrency = 3
+
+// runConcurrently calls fn for every index in [0, n) using at most workers
+// goroutines and returns once all calls have finished. Cancellation is left
+// to fn, whose requests fail fast once their context is done.
+func runConcurrently(workers, n int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+}
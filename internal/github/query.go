@@ -0,0 +1,233 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// Sort orders for search results
+const (
+	SortCreated      = "created"
+	SortUpdated      = "updated"
+	SortComments     = "comments"
+	SortReactions    = "reactions"
+	SortInteractions = "interactions"
+	// SortMixed runs each query once per ordering in MixedSorts
+	SortMixed = "mixed"
+)
+
+var sortOrders = []string{SortCreated, SortUpdated, SortComments, SortReactions, SortInteractions}
+
+// searchQuery is a search string together with the order its results are
+// requested in. Queries expanded from the same mixed query share a group.
+type searchQuery struct {
+	q     string
+	sort  string
+	group int
+}
+
+// String describes the query in errors and progress output
+func (sq searchQuery) String() string {
+	if sq.sort == SortCreated {
+		return sq.q
+	}
+	return fmt.Sprintf("%s (sorted by %s)", sq.q, sq.sort)
+}
+
+// DefaultSearchConfig returns the search settings used when none are configured
+func DefaultSearchConfig() types.SearchConfig {
+	return types.SearchConfig{
+		MaxPages:           defaultMaxPages,
+		MaxResultsPerQuery: defaultMaxResultsPerQuery,
+		CreatedWithin:      "180d",
+		Labels:             []string{"good first issue", "help wanted"},
+		MinComments:        1,
+		Sort:               SortCreated,
+		MixedSorts:         []string{SortCreated, SortComments, SortReactions},
+		BodyMaxChars:       issuebody.DefaultMaxChars,
+	}
+}
+
+// ValidateSearchConfig checks the relative date windows, comment bounds and
+// sort orders
+func ValidateSearchConfig(cfg types.SearchConfig) error {
+	check := func(scope string, createdWithin, updatedWithin string, minComments, maxComments int, sort string) error {
+		if sort != "" && sort != SortMixed && !slices.Contains(sortOrders, sort) {
+			return fmt.Errorf("%s: unknown sort %q (expected one of %s or %s)", scope, sort, strings.Join(sortOrders, ", "), SortMixed)
+		}
+		for _, window := range []string{createdWithin, updatedWithin} {
+			if _, err := source.ParseWindow(window, time.Now()); err != nil {
+				return fmt.Errorf("%s: %w", scope, err)
+			}
+		}
+		if maxComments > 0 && minComments > maxComments {
+			return fmt.Errorf("%s: min_comments (%d) is greater than max_comments (%d)", scope, minComments, maxComments)
+		}
+		return nil
+	}
+
+	if err := check("search", cfg.CreatedWithin, cfg.UpdatedWithin, cfg.MinComments, cfg.MaxComments, cfg.Sort); err != nil {
+		return err
+	}
+	for _, sort := range cfg.MixedSorts {
+		if !slices.Contains(sortOrders, sort) {
+			return fmt.Errorf("search: unknown mixed_sorts entry %q (expected one of %s)", sort, strings.Join(sortOrders, ", "))
+		}
+	}
+	if cfg.BodyMaxChars < 0 {
+		return fmt.Errorf("search: body_max_chars must not be negative")
+	}
+
+	for skill := range cfg.Skills {
+		criteria := queryCriteriaFor(cfg, skill)
+		if err := check("search.skills."+skill, criteria.createdWithin, criteria.updatedWithin, criteria.minComments, criteria.maxComments, criteria.sort); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queryCriteria is the effective search configuration for one skill
+type queryCriteria struct {
+	createdWithin string
+	updatedWithin string
+	labels        []string
+	minComments   int
+	maxComments   int
+	minReactions  int
+	sort          string
+}
+
+// queryCriteriaFor applies the per-skill overrides on top of the global settings
+func queryCriteriaFor(cfg types.SearchConfig, skill string) queryCriteria {
+	criteria := queryCriteria{
+		createdWithin: cfg.CreatedWithin,
+		updatedWithin: cfg.UpdatedWithin,
+		labels:        cfg.Labels,
+		minComments:   cfg.MinComments,
+		maxComments:   cfg.MaxComments,
+		minReactions:  cfg.MinReactions,
+		sort:          cfg.Sort,
+	}
+
+	override, ok := cfg.Skills[strings.ToLower(skill)]
+	if !ok {
+		return criteria
+	}
+
+	if override.CreatedWithin != "" {
+		criteria.createdWithin = override.CreatedWithin
+	}
+	if override.UpdatedWithin != "" {
+		criteria.updatedWithin = override.UpdatedWithin
+	}
+	if len(override.Labels) > 0 {
+		criteria.labels = override.Labels
+	}
+	if override.MinComments != nil {
+		criteria.minComments = *override.MinComments
+	}
+	if override.MaxComments != nil {
+		criteria.maxComments = *override.MaxComments
+	}
+	if override.MinReactions != nil {
+		criteria.minReactions = *override.MinReactions
+	}
+	if override.Sort != "" {
+		criteria.sort = override.Sort
+	}
+
+	return criteria
+}
+
+// buildSearchQueries builds targeted search queries based on skills and
+// interests, once per ordering for skills using the mixed sort
+func (gc *Client) buildSearchQueries(profile types.UserProfile) []searchQuery {
+	queries := []searchQuery{}
+	now := time.Now()
+	group := 0
+
+	add := func(q, sort string) {
+		sorts := []string{sort}
+		switch sort {
+		case "":
+			sorts = []string{SortCreated}
+		case SortMixed:
+			sorts = gc.search.MixedSorts
+			if len(sorts) == 0 {
+				sorts = DefaultSearchConfig().MixedSorts
+			}
+		}
+
+		for _, s := range sorts {
+			queries = append(queries, searchQuery{q: q, sort: s, group: group})
+		}
+		group++
+	}
+
+	for _, skill := range profile.Skills {
+		resolution := gc.skillMap.Resolve(skill)
+
+		criteria := queryCriteriaFor(gc.search, skill)
+		if _, ok := gc.search.Skills[strings.ToLower(skill)]; !ok {
+			criteria = queryCriteriaFor(gc.search, resolution.Canonical)
+		}
+		baseConstraints := gc.baseConstraints(criteria, now)
+
+		for _, qualifier := range resolution.Qualifiers {
+			if len(criteria.labels) == 0 {
+				add(fmt.Sprintf("%s %s", baseConstraints, qualifier), criteria.sort)
+				continue
+			}
+
+			for _, label := range criteria.labels {
+				add(fmt.Sprintf("%s %s label:\"%s\"", baseConstraints, qualifier, label), criteria.sort)
+			}
+		}
+	}
+
+	return queries
+}
+
+// baseConstraints renders the qualifiers shared by every query for a skill
+func (gc *Client) baseConstraints(criteria queryCriteria, now time.Time) string {
+	parts := []string{"is:issue", "is:open"}
+
+	if !gc.search.IncludeAssigned {
+		parts = append(parts, "no:assignee")
+	}
+
+	// Windows were validated at startup; invalid ones are skipped here
+	if since, err := source.ParseWindow(criteria.createdWithin, now); err == nil && !since.IsZero() {
+		parts = append(parts, "created:>"+since.Format("2006-01-02"))
+	}
+	if since, err := source.ParseWindow(criteria.updatedWithin, now); err == nil && !since.IsZero() {
+		parts = append(parts, "updated:>"+since.Format("2006-01-02"))
+	}
+
+	switch {
+	case criteria.minComments > 0 && criteria.maxComments > 0:
+		parts = append(parts, fmt.Sprintf("comments:%d..%d", criteria.minComments, criteria.maxComments))
+	case criteria.minComments > 0:
+		parts = append(parts, fmt.Sprintf("comments:>=%d", criteria.minComments))
+	case criteria.maxComments > 0:
+		parts = append(parts, fmt.Sprintf("comments:<=%d", criteria.maxComments))
+	}
+
+	if criteria.minReactions > 0 {
+		parts = append(parts, fmt.Sprintf("reactions:>=%d", criteria.minReactions))
+	}
+
+	if repoQualifiers := gc.repoQualifiers(); repoQualifiers != "" {
+		parts = append(parts, repoQualifiers)
+	}
+
+	return strings.Join(parts, " ")
+}
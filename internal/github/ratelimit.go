@@ -0,0 +1,269 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries  = 4
+	defaultBaseBackoff = 60 * time.Second
+	maxBackoff         = 15 * time.Minute
+)
+
+// rateLimiter tracks the quota GitHub reports for each rate limit resource
+// (core, search, graphql, ...) of one token. It is shared by all workers of
+// a client, so each request reserves one unit of quota up front and
+// concurrent workers cannot overshoot the limit.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	remaining int
+	reset     time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[string]*rateBucket),
+	}
+}
+
+// available returns the quota left for the resource and when it resets.
+// Unknown quota, or a window that has already reset, counts as unlimited
+// until a response reports the new quota.
+func (rl *rateLimiter) available(resource string) (int, time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	bucket := rl.buckets[resource]
+	if bucket == nil {
+		return math.MaxInt, time.Time{}
+	}
+	if bucket.remaining <= 0 && !time.Now().Before(bucket.reset) {
+		delete(rl.buckets, resource)
+		return math.MaxInt, time.Time{}
+	}
+	return bucket.remaining, bucket.reset
+}
+
+// reserve takes one request off the resource's known quota
+func (rl *rateLimiter) reserve(resource string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if bucket := rl.buckets[resource]; bucket != nil && bucket.remaining > 0 {
+		bucket.remaining--
+	}
+}
+
+// exhaust marks the resource as having no quota left until reset, for rate
+// limit responses that don't report their quota
+func (rl *rateLimiter) exhaust(resource string, reset time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if bucket := rl.buckets[resource]; bucket != nil && bucket.remaining == 0 && bucket.reset.After(reset) {
+		return
+	}
+	rl.buckets[resource] = &rateBucket{remaining: 0, reset: reset}
+}
+
+// update records the quota reported in the response headers
+func (rl *rateLimiter) update(resource string, header http.Header) {
+	if r := header.Get("X-RateLimit-Resource"); r != "" {
+		resource = r
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	reset := time.Now()
+	if epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(epoch, 0)
+	}
+
+	rl.mu.Lock()
+	rl.buckets[resource] = &rateBucket{remaining: remaining, reset: reset}
+	rl.mu.Unlock()
+}
+
+// sleepContext sleeps for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resourceFor guesses the rate limit resource a request is charged against
+func resourceFor(req *http.Request) string {
+	switch {
+	case strings.Contains(req.URL.Path, "/search/"):
+		return "search"
+	case strings.HasSuffix(req.URL.Path, "/graphql"):
+		return "graphql"
+	default:
+		return "core"
+	}
+}
+
+// do sends a request, going through the HTTP cache for GET requests when it
+// is enabled
+func (gc *Client) do(req *http.Request) (*http.Response, error) {
+	if gc.cache != nil && req.Method == http.MethodGet {
+		return gc.doCached(req)
+	}
+	return gc.send(req)
+}
+
+// send sends a request with a token from the pool, waiting for rate limit
+// quota beforehand. When GitHub answers with a primary or secondary rate
+// limit, the token is marked as limited until Retry-After, its reset time or
+// a backoff has passed, and the request is retried with the token that has
+// the most quota left.
+func (gc *Client) send(req *http.Request) (*http.Response, error) {
+	resource := resourceFor(req)
+
+	scheme := "token"
+	if resource == "graphql" {
+		scheme = "bearer"
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := gc.tokens.acquire(req.Context(), resource)
+		if err != nil {
+			return nil, err
+		}
+
+		credential, err := gc.credential(req.Context(), token)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", scheme+" "+credential)
+
+		resp, err := gc.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		token.limiter.update(resource, resp.Header)
+
+		limited, err := isRateLimited(resp, resource)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		if !limited {
+			return resp, nil
+		}
+
+		token.rateLimited.Add(1)
+		delay := gc.retryDelay(resp, attempt)
+		token.limiter.exhaust(resource, time.Now().Add(delay))
+
+		if attempt >= gc.maxRetries {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The next acquire moves on to another token, or waits for this
+		// one once every token is limited
+		log.Printf("GitHub rate limit hit on %s, retrying (attempt %d/%d)", token.name, attempt+1, gc.maxRetries)
+	}
+}
+
+// isRateLimited reports whether a response is a primary or secondary rate
+// limit rejection. GraphQL reports rate limits as a RATE_LIMITED error in a
+// 200 response. The body is buffered so callers can still read it.
+func isRateLimited(resp *http.Response, resource string) (bool, error) {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true, nil
+	}
+
+	graphQLOK := resource == "graphql" && resp.StatusCode == http.StatusOK
+	if resp.StatusCode != http.StatusForbidden && !graphQLOK {
+		return false, nil
+	}
+
+	if resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0") {
+		return true, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if graphQLOK {
+		var result struct {
+			Errors []struct {
+				Type string `json:"type"`
+			} `json:"errors"`
+		}
+		if json.Unmarshal(body, &result) != nil {
+			return false, nil
+		}
+		for _, gqlErr := range result.Errors {
+			if gqlErr.Type == "RATE_LIMITED" {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return strings.Contains(strings.ToLower(string(body)), "rate limit"), nil
+}
+
+// retryDelay picks how long to wait before retrying a rate limited request:
+// Retry-After if given, the reset time for an exhausted primary limit, and
+// otherwise exponential backoff as GitHub recommends for secondary limits
+func (gc *Client) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if delay := time.Until(time.Unix(epoch, 0)); delay > 0 {
+				return delay + time.Second
+			}
+		}
+	}
+
+	delay := gc.baseBackoff << attempt
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSecondaryRateLimitIsRetried(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"items":[%s]}`, searchItem(1))
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(2, time.Millisecond))
+
+	issues, _, err := gc.searchIssues(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+	if err != nil {
+		t.Fatalf("searchIssues() error = %v", err)
+	}
+	if len(issues) != 1 || requests.Load() != 2 {
+		t.Errorf("searchIssues() returned %d issues after %d requests, want 1 after a retry", len(issues), requests.Load())
+	}
+}
+
+func TestRateLimitErrorAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit"}`)
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(1, time.Millisecond))
+
+	_, _, err := gc.searchIssues(context.Background(), searchQuery{q: "is:issue", sort: SortCreated})
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || !rateErr.Secondary {
+		t.Errorf("searchIssues() error = %v, want a secondary RateLimitError", err)
+	}
+}
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// FetchRepository returns metadata for an "owner/name" repository. Results
+// are cached for the lifetime of the client.
+func (gc *Client) FetchRepository(ctx context.Context, fullName string) (*types.RepoMetadata, error) {
+	gc.repoMu.Lock()
+	cached, ok := gc.repoCache[fullName]
+	gc.repoMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var repo types.GitHubRepository
+	if err := gc.getJSON(ctx, gc.endpoint("/repos/"+fullName), &repo); err != nil {
+		return nil, err
+	}
+
+	metadata := &types.RepoMetadata{
+		FullName:        repo.FullName,
+		Stars:           repo.StargazersCount,
+		Forks:           repo.ForksCount,
+		OpenIssues:      repo.OpenIssuesCount,
+		PushedAt:        repo.PushedAt,
+		Archived:        repo.Archived,
+		Disabled:        repo.Disabled,
+		DefaultBranch:   repo.DefaultBranch,
+		PrimaryLanguage: repo.Language,
+	}
+	if repo.License != nil {
+		metadata.License = repo.License.SPDXID
+	}
+
+	gc.repoMu.Lock()
+	gc.repoCache[fullName] = metadata
+	gc.repoMu.Unlock()
+
+	return metadata, nil
+}
+
+// FilterRepositories attaches repository metadata to issues found by search
+// and drops those whose repository fails the health thresholds. Watched
+// repositories bypass the thresholds.
+func (gc *Client) FilterRepositories(ctx context.Context, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error) {
+	if len(gc.watchRepos) > 0 || len(issues) == 0 {
+		return issues, 0, nil
+	}
+
+	fetchErr := &source.FetchError{Source: gc.Name()}
+	kept, filtered := gc.enrichRepositories(ctx, issues, fetchErr)
+
+	if len(fetchErr.Errors) > 0 {
+		return kept, filtered, fetchErr
+	}
+	return kept, filtered, nil
+}
+
+// enrichRepositories attaches repository metadata to each candidate and drops
+// candidates whose repository fails the health thresholds. Candidates whose
+// metadata could not be fetched are kept and the error is recorded.
+func (gc *Client) enrichRepositories(ctx context.Context, issues []types.CandidateIssue, fetchErr *source.FetchError) ([]types.CandidateIssue, int) {
+	repoNames := []string{}
+	seenRepos := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Repository == nil && !seenRepos[issue.Repo] {
+			seenRepos[issue.Repo] = true
+			repoNames = append(repoNames, issue.Repo)
+		}
+	}
+
+	metadata := make([]*types.RepoMetadata, len(repoNames))
+	errs := make([]error, len(repoNames))
+	runConcurrently(gc.concurrency, len(repoNames), func(i int) {
+		metadata[i], errs[i] = gc.FetchRepository(ctx, repoNames[i])
+	})
+
+	repos := make(map[string]*types.RepoMetadata)
+	for i, name := range repoNames {
+		if errs[i] != nil {
+			fetchErr.Errors = append(fetchErr.Errors, &source.QueryError{Query: "repo:" + name, Err: errs[i]})
+			continue
+		}
+		repos[name] = metadata[i]
+	}
+
+	kept := []types.CandidateIssue{}
+	filtered := 0
+
+	for _, issue := range issues {
+		if issue.Repository == nil {
+			issue.Repository = repos[issue.Repo]
+		}
+
+		if issue.Repository != nil && !gc.healthyRepository(issue.Repository) {
+			filtered++
+			continue
+		}
+
+		kept = append(kept, issue)
+	}
+
+	return kept, filtered
+}
+
+// healthyRepository checks a repository against the configured thresholds
+func (gc *Client) healthyRepository(repo *types.RepoMetadata) bool {
+	if repo.Disabled {
+		return false
+	}
+
+	if repo.Archived && !gc.filters.IncludeArchived {
+		return false
+	}
+
+	if gc.filters.MinStars > 0 && repo.Stars < gc.filters.MinStars {
+		return false
+	}
+
+	if gc.filters.MaxInactiveDays > 0 && !repo.PushedAt.IsZero() {
+		inactive := time.Since(repo.PushedAt)
+		if inactive > time.Duration(gc.filters.MaxInactiveDays)*24*time.Hour {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+func TestRepositoriesAreLookedUpAfterSearch(t *testing.T) {
+	var repoLookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/search/issues":
+			fmt.Fprint(w, `{"items":[
+				{"number":1,"html_url":"https://github.com/o/live/issues/1","repository_url":"https://api.github.com/repos/o/live"},
+				{"number":2,"html_url":"https://github.com/o/old/issues/2","repository_url":"https://api.github.com/repos/o/old"}
+			]}`)
+		case strings.HasPrefix(r.URL.Path, "/repos/"):
+			repoLookups.Add(1)
+			fmt.Fprintf(w, `{"full_name":%q,"archived":%t}`, strings.TrimPrefix(r.URL.Path, "/repos/"), r.URL.Path == "/repos/o/old")
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gc := NewClient("token", WithBaseURL(server.URL))
+
+	result, err := gc.FetchRelevantIssues(context.Background(), types.UserProfile{Skills: []string{"Go"}})
+	if err != nil {
+		t.Fatalf("FetchRelevantIssues() error = %v", err)
+	}
+	if len(result.Issues) != 2 || repoLookups.Load() != 0 {
+		t.Fatalf("FetchRelevantIssues() returned %d issues after %d repository lookups, want 2 issues and no lookups", len(result.Issues), repoLookups.Load())
+	}
+
+	kept, filtered, err := gc.FilterRepositories(context.Background(), result.Issues)
+	if err != nil {
+		t.Fatalf("FilterRepositories() error = %v", err)
+	}
+	if len(kept) != 1 || filtered != 1 || kept[0].Repo != "o/live" {
+		t.Fatalf("FilterRepositories() kept %+v, filtered %d; want only o/live", kept, filtered)
+	}
+	if kept[0].Repository == nil || repoLookups.Load() != 2 {
+		t.Errorf("FilterRepositories() made %d lookups, want each repository looked up once", repoLookups.Load())
+	}
+}
@@ -0,0 +1,228 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// WithWatchRepos switches the client from global search to scanning the
+// open issues of the given "owner/name" repositories. Watched repositories
+// bypass the allow and deny lists and the health thresholds.
+func WithWatchRepos(repos []string) Option {
+	return func(gc *Client) {
+		gc.watchRepos = repos
+	}
+}
+
+// ValidateRepoName checks that name has the "owner/name" form
+func ValidateRepoName(name string) error {
+	owner, repo, ok := strings.Cut(name, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") || source.IsGlob(name) {
+		return fmt.Errorf("invalid repository %q (expected owner/name)", name)
+	}
+	return nil
+}
+
+// repoScan is one repository listed with one label
+type repoScan struct {
+	repo  string
+	label string
+}
+
+func (rs repoScan) String() string {
+	if rs.label == "" {
+		return "repo:" + rs.repo
+	}
+	return fmt.Sprintf("repo:%s label:%q", rs.repo, rs.label)
+}
+
+// scanRepositories lists the open issues of every watched repository, once
+// per configured label, following pagination to the end
+func (gc *Client) scanRepositories(ctx context.Context) (source.Result, error) {
+	labels := gc.search.Labels
+	if len(labels) == 0 {
+		labels = []string{""}
+	}
+
+	scans := []repoScan{}
+	for _, repo := range gc.watchRepos {
+		for _, label := range labels {
+			scans = append(scans, repoScan{repo: repo, label: label})
+		}
+	}
+
+	type scanResult struct {
+		issues []types.CandidateIssue
+		err    error
+	}
+
+	// An auth failure means every other listing will fail as well
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var authFailed atomic.Bool
+
+	results := make([]scanResult, len(scans))
+	runConcurrently(gc.concurrency, len(scans), func(i int) {
+		issues, err := gc.listRepoIssues(ctx, scans[i])
+		if isAuthError(err) {
+			authFailed.Store(true)
+			cancel()
+		}
+		results[i] = scanResult{issues: issues, err: err}
+	})
+
+	result := source.Result{Queries: len(scans)}
+	seenIssueURLs := make(map[string]bool)
+	fetchErr := &source.FetchError{Source: gc.Name()}
+
+	for i, scan := range scans {
+		if err := results[i].err; err != nil && !(authFailed.Load() && errors.Is(err, context.Canceled)) {
+			fetchErr.Errors = append(fetchErr.Errors, &source.QueryError{Query: scan.String(), Err: err})
+		}
+
+		for _, issue := range results[i].issues {
+			if seenIssueURLs[issue.URL] {
+				continue
+			}
+			seenIssueURLs[issue.URL] = true
+
+			issue.Source = gc.Name()
+			result.Issues = append(result.Issues, issue)
+		}
+	}
+
+	if len(result.Issues) > 0 && !authFailed.Load() && ctx.Err() == nil {
+		gc.attachRepositories(ctx, result.Issues, fetchErr)
+	}
+
+	if len(fetchErr.Errors) > 0 {
+		return result, fetchErr
+	}
+	return result, nil
+}
+
+// listRepoIssues lists the open issues of one repository, skipping pull
+// requests and applying the windows and bounds the endpoint can't filter on
+func (gc *Client) listRepoIssues(ctx context.Context, scan repoScan) ([]types.CandidateIssue, error) {
+	params := url.Values{}
+	params.Set("state", "open")
+	params.Set("sort", listSort(gc.search.Sort))
+	params.Set("direction", "desc")
+	params.Set("per_page", strconv.Itoa(maxPerPage))
+	if scan.label != "" {
+		params.Set("labels", scan.label)
+	}
+	if !gc.search.IncludeAssigned {
+		params.Set("assignee", "none")
+	}
+
+	// The listing only filters on update time; the created window is applied below
+	now := time.Now()
+	if since, err := source.ParseWindow(gc.search.UpdatedWithin, now); err == nil && !since.IsZero() {
+		params.Set("since", since.Format(time.RFC3339))
+	}
+	createdSince, _ := source.ParseWindow(gc.search.CreatedWithin, now)
+
+	pageURL := fmt.Sprintf("%s?%s", gc.endpoint("/repos/"+scan.repo+"/issues"), params.Encode())
+	candidates := []types.CandidateIssue{}
+
+	for pageURL != "" {
+		issues, nextURL, err := gc.listIssuesPage(ctx, pageURL)
+		if err != nil {
+			return candidates, err
+		}
+
+		for _, issue := range issues {
+			if issue.PullRequest != nil || issue.CreatedAt.Before(createdSince) || !gc.withinBounds(issue) {
+				continue
+			}
+			candidates = append(candidates, gc.toCandidate(issue))
+		}
+
+		pageURL = nextURL
+	}
+
+	return candidates, nil
+}
+
+// listIssuesPage fetches one page of a repository issue listing and returns
+// its issues and the URL of the next page, if any
+func (gc *Client) listIssuesPage(ctx context.Context, pageURL string) ([]types.GitHubIssue, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := gc.do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, "", err
+	}
+
+	var issues []types.GitHubIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, "", &DecodeError{Err: err}
+	}
+
+	return issues, nextPageURL(resp.Header.Get("Link")), nil
+}
+
+// withinBounds applies the comment and reaction bounds of the search config
+func (gc *Client) withinBounds(issue types.GitHubIssue) bool {
+	if gc.search.MinComments > 0 && issue.Comments < gc.search.MinComments {
+		return false
+	}
+	if gc.search.MaxComments > 0 && issue.Comments > gc.search.MaxComments {
+		return false
+	}
+	if gc.search.MinReactions > 0 && issue.Reactions.TotalCount < gc.search.MinReactions {
+		return false
+	}
+	return true
+}
+
+// listSort maps a search sort onto the orders the issue listing supports
+func listSort(sort string) string {
+	switch sort {
+	case SortUpdated, SortComments:
+		return sort
+	default:
+		return SortCreated
+	}
+}
+
+// attachRepositories attaches repository metadata to each candidate without
+// applying the health thresholds
+func (gc *Client) attachRepositories(ctx context.Context, issues []types.CandidateIssue, fetchErr *source.FetchError) {
+	repos := make(map[string]*types.RepoMetadata)
+
+	for i := range issues {
+		metadata, seen := repos[issues[i].Repo]
+		if !seen {
+			var err error
+			metadata, err = gc.FetchRepository(ctx, issues[i].Repo)
+			if err != nil {
+				fetchErr.Errors = append(fetchErr.Errors, &source.QueryError{Query: "repo:" + issues[i].Repo, Err: err})
+			}
+			repos[issues[i].Repo] = metadata
+		}
+		issues[i].Repository = metadata
+	}
+}
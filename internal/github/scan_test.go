@@ -0,0 +1,54 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+func TestScanDetectsClaimsWithGraphQLBackend(t *testing.T) {
+	created := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/issues":
+			fmt.Fprintf(w, `[{"number":1,"title":"Fix flag parsing","html_url":"https://github.com/o/r/issues/1","repository_url":"https://api.github.com/repos/o/r","comments":1,"created_at":%q}]`, created)
+		case "/repos/o/r":
+			fmt.Fprint(w, `{"full_name":"o/r","stargazers_count":10}`)
+		case "/repos/o/r/issues/1/comments":
+			fmt.Fprint(w, `[{"user":{"login":"someone"},"author_association":"NONE","body":"I'll take this"}]`)
+		case "/repos/o/r/issues/1/timeline":
+			fmt.Fprint(w, `[]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gc := NewClient("token",
+		WithBaseURL(server.URL),
+		WithBackend(BackendGraphQL),
+		WithWatchRepos([]string{"o/r"}),
+	)
+
+	result, err := gc.FetchRelevantIssues(context.Background(), types.UserProfile{Skills: []string{"Go"}})
+	if err != nil {
+		t.Fatalf("FetchRelevantIssues() error = %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("FetchRelevantIssues() returned %d issues, want 1", len(result.Issues))
+	}
+
+	kept, claimed, err := gc.DetectClaims(context.Background(), result.Issues)
+	if err != nil {
+		t.Fatalf("DetectClaims() error = %v", err)
+	}
+	if claimed != 1 || len(kept) != 0 {
+		t.Errorf("DetectClaims() kept %d, claimed %d; want the scanned issue dropped as claimed", len(kept), claimed)
+	}
+}
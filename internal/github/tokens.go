@@ -0,0 +1,166 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Token is a GitHub token together with a name used when reporting usage,
+// such as the environment variable it was read from. The name must not
+// reveal the token itself.
+type Token struct {
+	Name  string
+	Value string
+}
+
+// TokenUsage reports how a token of the pool was used during a run
+type TokenUsage struct {
+	Name        string
+	Requests    int64
+	RateLimited int64
+	// Remaining is the last known quota per rate limit resource
+	Remaining map[string]int
+}
+
+// pooledToken is a token with its own rate limit quota and usage counters.
+// GitHub App tokens have no fixed value and are fetched through app.
+type pooledToken struct {
+	name        string
+	value       string
+	app         *AppAuth
+	limiter     *rateLimiter
+	requests    atomic.Int64
+	rateLimited atomic.Int64
+}
+
+// tokenPool hands out the tokens of a client. Requests stay on the current
+// token until its quota for a resource runs out or GitHub rate limits it,
+// then move to the token with the most quota left.
+type tokenPool struct {
+	mu      sync.Mutex
+	tokens  []*pooledToken
+	current map[string]int
+}
+
+// WithTokens rotates requests between several tokens instead of the single
+// token passed to NewClient. Each token keeps its own rate limit quota.
+func WithTokens(tokens []Token) Option {
+	return func(gc *Client) {
+		if len(tokens) > 0 {
+			gc.tokens = newTokenPool(tokens)
+		}
+	}
+}
+
+func newTokenPool(tokens []Token) *tokenPool {
+	pool := &tokenPool{current: make(map[string]int)}
+	for _, t := range tokens {
+		pool.tokens = append(pool.tokens, &pooledToken{
+			name:    t.Name,
+			value:   t.Value,
+			limiter: newRateLimiter(),
+		})
+	}
+	return pool
+}
+
+// acquire picks a token with quota left for the resource and reserves one
+// request against it. When every token is exhausted it waits for the
+// earliest reset.
+func (p *tokenPool) acquire(ctx context.Context, resource string) (*pooledToken, error) {
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("no GitHub token configured")
+	}
+
+	for {
+		p.mu.Lock()
+		current := p.tokens[p.current[resource]]
+
+		if remaining, _ := current.limiter.available(resource); remaining > 0 {
+			current.limiter.reserve(resource)
+			current.requests.Add(1)
+			p.mu.Unlock()
+			return current, nil
+		}
+
+		best, bestRemaining := -1, 0
+		var earliestReset time.Time
+		for i, t := range p.tokens {
+			remaining, reset := t.limiter.available(resource)
+			if remaining > bestRemaining {
+				best, bestRemaining = i, remaining
+			}
+			if remaining == 0 && (earliestReset.IsZero() || reset.Before(earliestReset)) {
+				earliestReset = reset
+			}
+		}
+
+		if best >= 0 {
+			next := p.tokens[best]
+			p.current[resource] = best
+			next.limiter.reserve(resource)
+			next.requests.Add(1)
+			p.mu.Unlock()
+
+			log.Printf("GitHub %s quota exhausted on %s, switching to %s", resource, current.name, next.name)
+			return next, nil
+		}
+		p.mu.Unlock()
+
+		delay := time.Until(earliestReset)
+		if len(p.tokens) > 1 {
+			log.Printf("GitHub %s rate limit exhausted on all %d tokens, waiting %s for reset", resource, len(p.tokens), delay.Round(time.Second))
+		} else {
+			log.Printf("GitHub %s rate limit exhausted, waiting %s for reset", resource, delay.Round(time.Second))
+		}
+		if err := sleepContext(ctx, delay+time.Second); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// credential returns the token to send, fetching a fresh installation token
+// for GitHub Apps when needed
+func (gc *Client) credential(ctx context.Context, t *pooledToken) (string, error) {
+	if t.app != nil {
+		return gc.installationToken(ctx, t.app)
+	}
+	return t.value, nil
+}
+
+// usage reports the requests made with each token and its last known quota
+func (p *tokenPool) usage() []TokenUsage {
+	usage := make([]TokenUsage, 0, len(p.tokens))
+
+	for _, t := range p.tokens {
+		remaining := make(map[string]int)
+		t.limiter.mu.Lock()
+		for resource, bucket := range t.limiter.buckets {
+			remaining[resource] = bucket.remaining
+		}
+		t.limiter.mu.Unlock()
+
+		usage = append(usage, TokenUsage{
+			Name:        t.name,
+			Requests:    t.requests.Load(),
+			RateLimited: t.rateLimited.Load(),
+			Remaining:   remaining,
+		})
+	}
+
+	return usage
+}
+
+// TokenUsage reports per-token request counts and remaining quota
+func (gc *Client) TokenUsage() []TokenUsage {
+	return gc.tokens.usage()
+}
+
+// TokenCount returns how many tokens the client rotates between
+func (gc *Client) TokenCount() int {
+	return len(gc.tokens.tokens)
+}
@@ -0,0 +1,357 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/internal/issuebody"
+	"github.com/ashishra0/issue-finder/internal/skillmap"
+	"github.com/ashishra0/issue-finder/internal/source"
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// DefaultBaseURL is the root of gitlab.com
+const DefaultBaseURL = "https://gitlab.com"
+
+const (
+	maxPerPage              = 100
+	defaultProjectsPerQuery = 20
+)
+
+// Client searches a GitLab instance through its REST API (v4). GitLab has
+// no global issue search by language, so each query lists projects by
+// topic or language and then lists their open issues.
+type Client struct {
+	name             string
+	baseURL          string
+	api              *source.APIClient
+	search           types.SearchConfig
+	skillMap         *skillmap.Map
+	projectsPerQuery int
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithSearchConfig sets the labels, date windows, comment bounds and
+// pagination limits. Per-skill overrides are not applied on GitLab.
+func WithSearchConfig(cfg types.SearchConfig) Option {
+	return func(c *Client) {
+		c.search = cfg
+	}
+}
+
+// WithSkillMap sets how profile skills translate into project filters.
+// "language:" qualifiers become with_programming_language and "topic:"
+// qualifiers become topic; other qualifiers are ignored.
+func WithSkillMap(skillMap *skillmap.Map) Option {
+	return func(c *Client) {
+		if skillMap != nil {
+			c.skillMap = skillMap
+		}
+	}
+}
+
+// WithProjectsPerQuery limits how many projects are scanned per query
+func WithProjectsPerQuery(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.projectsPerQuery = n
+		}
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.api.HTTPClient = httpClient
+		}
+	}
+}
+
+// WithRetryPolicy sets how many times a rate limited request is retried and
+// the initial backoff used when GitLab sends no Retry-After
+func WithRetryPolicy(maxRetries int, baseBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.api.MaxRetries = maxRetries
+		c.api.BaseBackoff = baseBackoff
+	}
+}
+
+// NewClient creates a client for the GitLab instance at baseURL. An empty
+// name defaults to the host of baseURL.
+func NewClient(name, baseURL, token string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	if name == "" {
+		name = baseURL
+		if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+			name = parsed.Host
+		}
+	}
+
+	c := &Client{
+		name:    name,
+		baseURL: baseURL,
+		api: &source.APIClient{
+			Forge:      "GitLab",
+			HTTPClient: &http.Client{Timeout: 30 * time.Second},
+			Header:     http.Header{},
+		},
+		search: types.SearchConfig{
+			MaxPages:           1,
+			MaxResultsPerQuery: 50,
+		},
+		skillMap:         skillmap.Default(),
+		projectsPerQuery: defaultProjectsPerQuery,
+	}
+
+	if token != "" {
+		c.api.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name identifies the source in state keys and output
+func (c *Client) Name() string {
+	return c.name
+}
+
+type project struct {
+	ID                int       `json:"id"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	StarCount         int       `json:"star_count"`
+	ForksCount        int       `json:"forks_count"`
+	OpenIssuesCount   int       `json:"open_issues_count"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+	Archived          bool      `json:"archived"`
+	DefaultBranch     string    `json:"default_branch"`
+}
+
+type issue struct {
+	IID            int       `json:"iid"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	WebURL         string    `json:"web_url"`
+	Labels         []string  `json:"labels"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	UserNotesCount int       `json:"user_notes_count"`
+	Upvotes        int       `json:"upvotes"`
+}
+
+// query is one project filter combined with one label
+type query struct {
+	description string
+	projects    url.Values
+	label       string
+}
+
+// FetchRelevantIssues lists open issues from projects matching the profile
+// skills. Failed queries are reported in a *source.FetchError while issues
+// from the other queries are still returned.
+func (c *Client) FetchRelevantIssues(ctx context.Context, profile types.UserProfile) (source.Result, error) {
+	return source.CollectIssues(ctx, c.name, c.buildQueries(profile), func(q query) string { return q.description }, c.runQuery)
+}
+
+// buildQueries turns each skill qualifier into project filters, once per label
+func (c *Client) buildQueries(profile types.UserProfile) []query {
+	queries := []query{}
+
+	labels := c.search.Labels
+	if len(labels) == 0 {
+		labels = []string{""}
+	}
+
+	for _, skill := range profile.Skills {
+		for _, qualifier := range c.skillMap.Resolve(skill).Qualifiers {
+			params := projectFilters(qualifier)
+			if params == nil {
+				continue
+			}
+
+			for _, label := range labels {
+				description := qualifier
+				if label != "" {
+					description += fmt.Sprintf(" label:%q", label)
+				}
+				queries = append(queries, query{description: description, projects: params, label: label})
+			}
+		}
+	}
+
+	return queries
+}
+
+// projectFilters maps a qualifier such as "language:ruby topic:rails" onto
+// project listing parameters, or returns nil if nothing can be mapped
+func projectFilters(qualifier string) url.Values {
+	params := url.Values{}
+
+	for _, part := range strings.Fields(qualifier) {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "language":
+			params.Set("with_programming_language", value)
+		case "topic":
+			params.Set("topic", value)
+		}
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+func (c *Client) runQuery(ctx context.Context, q query) ([]types.CandidateIssue, error) {
+	params := url.Values{}
+	for key, values := range q.projects {
+		params[key] = values
+	}
+	params.Set("archived", "false")
+	params.Set("order_by", "last_activity_at")
+	params.Set("sort", "desc")
+	params.Set("per_page", strconv.Itoa(min(c.projectsPerQuery, maxPerPage)))
+
+	var projects []project
+	if _, err := c.getJSON(ctx, "/projects?"+params.Encode(), &projects); err != nil {
+		return nil, err
+	}
+
+	candidates := []types.CandidateIssue{}
+	for _, p := range projects {
+		issues, err := c.listIssues(ctx, p, q.label)
+		if err != nil {
+			return candidates, err
+		}
+		candidates = append(candidates, issues...)
+	}
+
+	return candidates, nil
+}
+
+// listIssues lists open, unassigned issues of a project, following
+// pagination up to the configured limits
+func (c *Client) listIssues(ctx context.Context, p project, label string) ([]types.CandidateIssue, error) {
+	maxResults := c.search.MaxResultsPerQuery
+	if maxResults <= 0 {
+		maxResults = maxPerPage
+	}
+
+	params := url.Values{}
+	params.Set("state", "opened")
+	params.Set("order_by", "created_at")
+	params.Set("sort", "desc")
+	params.Set("per_page", strconv.Itoa(min(maxResults, maxPerPage)))
+	if label != "" {
+		params.Set("labels", label)
+	}
+	if !c.search.IncludeAssigned {
+		params.Set("assignee_id", "None")
+	}
+
+	now := time.Now()
+	if since, err := source.ParseWindow(c.search.CreatedWithin, now); err == nil && !since.IsZero() {
+		params.Set("created_after", since.Format(time.RFC3339))
+	}
+	if since, err := source.ParseWindow(c.search.UpdatedWithin, now); err == nil && !since.IsZero() {
+		params.Set("updated_after", since.Format(time.RFC3339))
+	}
+
+	metadata := &types.RepoMetadata{
+		FullName:      p.PathWithNamespace,
+		Stars:         p.StarCount,
+		Forks:         p.ForksCount,
+		OpenIssues:    p.OpenIssuesCount,
+		PushedAt:      p.LastActivityAt,
+		Archived:      p.Archived,
+		DefaultBranch: p.DefaultBranch,
+	}
+
+	candidates := []types.CandidateIssue{}
+	page := "1"
+
+	for i := 0; i < max(c.search.MaxPages, 1) && page != ""; i++ {
+		params.Set("page", page)
+
+		var issues []issue
+		header, err := c.getJSON(ctx, fmt.Sprintf("/projects/%d/issues?%s", p.ID, params.Encode()), &issues)
+		if err != nil {
+			return candidates, err
+		}
+
+		for _, iss := range issues {
+			if !c.withinBounds(iss) {
+				continue
+			}
+			candidates = append(candidates, c.toCandidate(iss, metadata))
+		}
+
+		if len(candidates) >= maxResults {
+			return candidates[:maxResults], nil
+		}
+
+		page = header.Get("X-Next-Page")
+	}
+
+	return candidates, nil
+}
+
+// withinBounds applies the comment and reaction bounds GitLab can't filter on
+func (c *Client) withinBounds(iss issue) bool {
+	if c.search.MinComments > 0 && iss.UserNotesCount < c.search.MinComments {
+		return false
+	}
+	if c.search.MaxComments > 0 && iss.UserNotesCount > c.search.MaxComments {
+		return false
+	}
+	if c.search.MinReactions > 0 && iss.Upvotes < c.search.MinReactions {
+		return false
+	}
+	return true
+}
+
+func (c *Client) toCandidate(iss issue, metadata *types.RepoMetadata) types.CandidateIssue {
+	labels := iss.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	return types.CandidateIssue{
+		Source:     c.name,
+		Repo:       metadata.FullName,
+		Number:     iss.IID,
+		Title:      iss.Title,
+		URL:        iss.WebURL,
+		Labels:     labels,
+		Body:       issuebody.Prepare(iss.Description, c.search.BodyMaxChars),
+		CreatedAt:  iss.CreatedAt,
+		UpdatedAt:  iss.UpdatedAt,
+		Comments:   iss.UserNotesCount,
+		Repository: metadata,
+	}
+}
+
+// getJSON performs a GET request against the API and decodes the response
+func (c *Client) getJSON(ctx context.Context, path string, v any) (http.Header, error) {
+	return c.api.GetJSON(ctx, c.baseURL+"/api/v4"+path, v)
+}
@@ -0,0 +1,289 @@
+// Package issuebody turns raw issue markdown into a short excerpt for
+// evaluation. Issue templates fill bodies with HTML comments, checklists and
+// empty form fields, so a plain prefix of the body often says nothing about
+// the actual problem.
+package issuebody
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxChars is the character budget used when none is configured
+const DefaultMaxChars = 800
+
+// TruncatedMarker is appended when content was dropped to fit the budget
+const TruncatedMarker = "... [truncated]"
+
+// minFragment is the smallest budget worth spending on part of a paragraph
+const minFragment = 80
+
+var (
+	htmlComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	checklist    = regexp.MustCompile(`^\s*[-*+]\s+\[[ xX]\]`)
+	image        = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	heading      = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	emptyFields  = []string{"no response", "n/a", "none"}
+	keyHeadingRe = regexp.MustCompile(`(?i)\b(summary|describ|problem|expected|actual|current|proposed|solution|feature|motivation|steps|reproduce|acceptance|context|goal)`)
+)
+
+// section is a heading and the paragraphs below it. The lead section before
+// the first heading has an empty heading.
+type section struct {
+	heading    string
+	paragraphs []string
+	key        bool
+}
+
+// Prepare strips HTML comments, checklists, images and empty template
+// fields from an issue body and cuts it down to maxChars characters (runes).
+// Sections under headings such as "Expected behavior" or "Proposed
+// solution" are kept first, and cuts fall on paragraph boundaries where
+// possible. A maxChars of 0 uses DefaultMaxChars.
+func Prepare(body string, maxChars int) string {
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChars
+	}
+
+	sections := parse(clean(body))
+	if len(sections) == 0 {
+		return ""
+	}
+
+	// Fill the budget with key sections first, then the lead, then the rest
+	order := make([]int, 0, len(sections))
+	for pass := 0; pass < 3; pass++ {
+		for i, s := range sections {
+			isLead := i == 0 && s.heading == ""
+			if (pass == 0 && s.key) || (pass == 1 && isLead) || (pass == 2 && !s.key && !isLead) {
+				order = append(order, i)
+			}
+		}
+	}
+
+	kept := make([][]string, len(sections))
+	budget := maxChars
+	truncated := false
+
+	// Leave room for the marker if everything won't fit
+	total := 0
+	for _, s := range sections {
+		if s.heading != "" {
+			total += runeLen(s.heading) + 2
+		}
+		for _, paragraph := range s.paragraphs {
+			total += runeLen(paragraph) + 2
+		}
+	}
+	if total-2 > maxChars {
+		budget -= runeLen(TruncatedMarker) + 1
+	}
+	fullBudget := budget
+
+	// The first paragraph that doesn't fit is skipped along with the rest of
+	// its section, so shorter sections later in the order still get a
+	// chance. It is cut down to whatever budget is left at the end.
+	cutSection, cutParagraph := -1, 0
+
+	for _, i := range order {
+		s := sections[i]
+
+		// A heading is only worth its cost with at least some content
+		headingCost := 0
+		if s.heading != "" {
+			headingCost = runeLen(s.heading) + 2
+		}
+
+		for j, paragraph := range s.paragraphs {
+			cost := runeLen(paragraph) + 2
+			if j == 0 {
+				cost += headingCost
+			}
+
+			if cost <= budget {
+				kept[i] = append(kept[i], paragraph)
+				budget -= cost
+				continue
+			}
+
+			truncated = true
+			if cutSection == -1 {
+				cutSection, cutParagraph = i, j
+			}
+			break
+		}
+	}
+
+	// A fragment too small to be useful is dropped, unless nothing else fit
+	if cutSection != -1 {
+		remaining := budget - 2
+		if cutParagraph == 0 && sections[cutSection].heading != "" {
+			remaining -= runeLen(sections[cutSection].heading) + 2
+		}
+		if remaining >= minFragment || remaining > 0 && budget == fullBudget {
+			kept[cutSection] = append(kept[cutSection], truncate(sections[cutSection].paragraphs[cutParagraph], remaining))
+		}
+	}
+
+	var b strings.Builder
+	for i, s := range sections {
+		if len(kept[i]) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		if s.heading != "" {
+			b.WriteString(s.heading)
+			b.WriteString("\n\n")
+		}
+		b.WriteString(strings.Join(kept[i], "\n\n"))
+	}
+
+	// Budgets too small for the marker get none
+	if truncated && runeLen(TruncatedMarker) <= maxChars {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(TruncatedMarker)
+	}
+
+	return b.String()
+}
+
+// clean removes markup that carries no information for evaluation
+func clean(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = htmlComment.ReplaceAllString(body, "")
+	body = image.ReplaceAllString(body, "")
+	return body
+}
+
+// parse splits a body into sections of paragraphs. Fenced code blocks stay
+// in one paragraph, checklists and empty form fields are dropped, and
+// sections left without content are skipped.
+func parse(body string) []section {
+	sections := []section{{}}
+	var paragraph []string
+	inFence := false
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(paragraph, "\n"))
+		paragraph = nil
+		if text == "" || isEmptyField(text) {
+			return
+		}
+		current := &sections[len(sections)-1]
+		current.paragraphs = append(current.paragraphs, text)
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if !inFence {
+				flush()
+			}
+			paragraph = append(paragraph, strings.TrimRightFunc(line, unicode.IsSpace))
+			inFence = !inFence
+			if !inFence {
+				flush()
+			}
+			continue
+		}
+
+		if inFence {
+			paragraph = append(paragraph, line)
+			continue
+		}
+
+		if match := heading.FindStringSubmatch(line); match != nil {
+			flush()
+			sections = append(sections, section{
+				heading: strings.TrimSpace(line),
+				key:     keyHeadingRe.MatchString(match[1]),
+			})
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if checklist.MatchString(line) {
+			continue
+		}
+
+		paragraph = append(paragraph, strings.TrimRightFunc(line, unicode.IsSpace))
+	}
+	flush()
+
+	nonEmpty := sections[:0]
+	for _, s := range sections {
+		if len(s.paragraphs) > 0 {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return nonEmpty
+}
+
+func isEmptyField(text string) bool {
+	text = strings.ToLower(strings.Trim(text, " *_."))
+	for _, empty := range emptyFields {
+		if text == empty {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate cuts text to at most maxChars runes, preferring the end of a
+// sentence or word, and closes a fenced code block left open by the cut
+func truncate(text string, maxChars int) string {
+	if runeLen(text) <= maxChars {
+		return text
+	}
+
+	fence := ""
+	if strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+		fence = text[:3]
+		maxChars -= len(fence) + 1
+	}
+
+	count := 0
+	for i := range text {
+		if count == maxChars {
+			text = text[:i]
+			break
+		}
+		count++
+	}
+
+	if i := strings.LastIndexAny(text, ".!?\n"); i > len(text)/2 {
+		text = text[:i+1]
+	} else if i := strings.LastIndexFunc(text, unicode.IsSpace); i > len(text)/2 {
+		text = text[:i]
+	}
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+
+	if fence != "" {
+		text += "\n" + fence
+	}
+	return text
+}
+
+// Truncate shortens text such as a comment to at most maxChars runes
+// without splitting a rune, appending "..." when it was cut
+func Truncate(text string, maxChars int) string {
+	if runeLen(text) <= maxChars {
+		return text
+	}
+	return truncate(text, maxChars) + "..."
+}
+
+func runeLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
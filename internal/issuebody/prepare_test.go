@@ -0,0 +1,122 @@
+package issuebody
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPrepare(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		maxChars int
+		want     string
+	}{
+		{
+			name:     "empty",
+			body:     "",
+			maxChars: 100,
+			want:     "",
+		},
+		{
+			name:     "fits unchanged",
+			body:     "The flag parser panics on empty input.",
+			maxChars: 100,
+			want:     "The flag parser panics on empty input.",
+		},
+		{
+			name: "strips template noise",
+			body: "<!-- Please fill in the sections below -->\r\n" +
+				"### Describe the bug\r\n\r\nThe flag parser panics.\r\n\r\n" +
+				"### Additional context\r\n\r\n_No response_\r\n\r\n" +
+				"- [ ] I searched existing issues\r\n" +
+				"![screenshot](https://example.com/a.png)\r\n",
+			maxChars: 200,
+			want:     "### Describe the bug\n\nThe flag parser panics.",
+		},
+		{
+			name: "key sections first",
+			body: "Some background that goes on for a while about how the project started.\n\n" +
+				"## Describe the bug\n\nCrash on empty input.",
+			maxChars: 60,
+			want:     "## Describe the bug\n\nCrash on empty input. ... [truncated]",
+		},
+		{
+			name:     "heading with one long paragraph",
+			body:     "# Title\n\n" + strings.Repeat("a", 2000),
+			maxChars: 100,
+			want:     "# Title\n\n" + strings.Repeat("a", 73) + " ... [truncated]",
+		},
+		{
+			name: "short key section after one that doesn't fit",
+			body: "## Summary\n\n" + strings.Repeat("word ", 60) + "\n\n" +
+				"## Expected behavior\n\nNo crash.",
+			maxChars: 150,
+			want: "## Summary\n\n" + strings.TrimSpace(strings.Repeat("word ", 17)) + "\n\n" +
+				"## Expected behavior\n\nNo crash. ... [truncated]",
+		},
+		{
+			name:     "cuts at a sentence",
+			body:     strings.Repeat("One sentence here. ", 20),
+			maxChars: 100,
+			want:     strings.TrimSpace(strings.Repeat("One sentence here. ", 4)) + " ... [truncated]",
+		},
+		{
+			name:     "code fence kept whole",
+			body:     "Run this:\n\n```go\nfunc main() {\n\n\tpanic(1)\n}\n```\n\n- [x] not a checklist inside\n",
+			maxChars: 200,
+			want:     "Run this:\n\n```go\nfunc main() {\n\n\tpanic(1)\n}\n```",
+		},
+		{
+			name:     "code fence closed after a cut",
+			body:     "```\n" + strings.Repeat("line of code\n", 50) + "```",
+			maxChars: 100,
+			want:     "```\n" + strings.TrimSpace(strings.Repeat("line of code\n", 5)) + "\n``` ... [truncated]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Prepare(tt.body, tt.maxChars)
+			if got != tt.want {
+				t.Errorf("Prepare() = %q\nwant %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.maxChars {
+				t.Errorf("Prepare() returned %d characters, budget is %d", n, tt.maxChars)
+			}
+		})
+	}
+}
+
+func TestPrepareBudgets(t *testing.T) {
+	body := "## Summary\n\n" + strings.Repeat("Ünïcödé wörds and 日本語 text. ", 100) + "\n\n## Steps\n\n1. Run it\n2. See it crash"
+
+	for _, maxChars := range []int{1, 20, 50, 81, 100, 333, 1000, 5000} {
+		got := Prepare(body, maxChars)
+		if !utf8.ValidString(got) {
+			t.Errorf("Prepare(%d) returned invalid UTF-8: %q", maxChars, got)
+		}
+		if n := utf8.RuneCountInString(got); n > maxChars {
+			t.Errorf("Prepare(%d) returned %d characters", maxChars, n)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxChars int
+		want     string
+	}{
+		{text: "short", maxChars: 10, want: "short"},
+		{text: "日本語のテキストです", maxChars: 4, want: "日本語の..."},
+		{text: "the quick brown fox jumps", maxChars: 12, want: "the quick..."},
+	}
+
+	for _, tt := range tests {
+		if got := Truncate(tt.text, tt.maxChars); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.maxChars, got, tt.want)
+		}
+	}
+}
@@ -13,7 +13,7 @@ import (
 func WriteMarkdownFile(outputPath string, state types.State) error {
 	var sb strings.Builder
 
-	sb.WriteString("# GitHub OSS Contribution Opportunities\n\n")
+	sb.WriteString("# OSS Contribution Opportunities\n\n")
 	sb.WriteString(fmt.Sprintf("Last updated: %s\n\n", time.Now().Format("2006-01-02 15:04")))
 	sb.WriteString(fmt.Sprintf("Total opportunities: %d\n\n", len(state.AllMatches)))
 	sb.WriteString("---\n\n")
@@ -25,6 +25,9 @@ func WriteMarkdownFile(outputPath string, state types.State) error {
 			sb.WriteString(fmt.Sprintf("## [%s] %s\n\n", match.Repo, match.Title))
 
 			sb.WriteString(fmt.Sprintf("- **URL**: %s\n", match.URL))
+			if match.Source != "" && match.Source != types.DefaultSource {
+				sb.WriteString(fmt.Sprintf("- **Source**: %s\n", match.Source))
+			}
 			sb.WriteString(fmt.Sprintf("- **Effort**: %s\n", match.Effort))
 			sb.WriteString(fmt.Sprintf("- **Created**: %s\n", match.CreatedAt))
 			sb.WriteString(fmt.Sprintf("- **Found**: %s\n", match.FoundAt))
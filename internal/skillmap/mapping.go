@@ -0,0 +1,226 @@
+package skillmap
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Where a skill's qualifiers came from
+const (
+	SourceBuiltin  = "built-in"
+	SourceUser     = "user"
+	SourceFallback = "fallback"
+)
+
+// Mapping translates a skill into GitHub search qualifiers. Each qualifier
+// is a query fragment such as "language:go" or "language:ruby topic:rails"
+// and gets its own search query.
+type Mapping struct {
+	Name       string   `mapstructure:"name" yaml:"name"`
+	Aliases    []string `mapstructure:"aliases" yaml:"aliases"`
+	Qualifiers []string `mapstructure:"qualifiers" yaml:"qualifiers"`
+}
+
+// Resolution is the result of looking up a skill
+type Resolution struct {
+	Skill      string
+	Canonical  string
+	Qualifiers []string
+	Source     string
+}
+
+// Map holds skill mappings indexed by lower-cased name and alias
+type Map struct {
+	mappings map[string]Mapping
+	sources  map[string]string
+	aliases  map[string]string
+}
+
+var builtinMappings = []Mapping{
+	{Name: "python", Aliases: []string{"py"}, Qualifiers: []string{"language:python"}},
+	{Name: "ruby on rails", Aliases: []string{"rails", "ror"}, Qualifiers: []string{"language:ruby topic:rails"}},
+	{Name: "ruby", Qualifiers: []string{"language:ruby"}},
+	{Name: "go", Aliases: []string{"golang"}, Qualifiers: []string{"language:go"}},
+	{Name: "rust", Qualifiers: []string{"language:rust"}},
+	{Name: "javascript", Aliases: []string{"js"}, Qualifiers: []string{"language:javascript"}},
+	{Name: "typescript", Aliases: []string{"ts"}, Qualifiers: []string{"language:typescript"}},
+	{Name: "java", Qualifiers: []string{"language:java"}},
+	{Name: "c++", Aliases: []string{"cpp"}, Qualifiers: []string{"language:c++"}},
+	{Name: "c", Qualifiers: []string{"language:c"}},
+	{Name: "c#", Aliases: []string{"csharp"}, Qualifiers: []string{"language:c#"}},
+	{Name: "php", Qualifiers: []string{"language:php"}},
+	{Name: "swift", Qualifiers: []string{"language:swift"}},
+	{Name: "kotlin", Qualifiers: []string{"language:kotlin"}},
+	{Name: "postgresql", Aliases: []string{"postgres"}, Qualifiers: []string{"topic:postgresql"}},
+	{Name: "sqlite", Qualifiers: []string{"topic:sqlite"}},
+	{Name: "mysql", Qualifiers: []string{"topic:mysql"}},
+	{Name: "mongodb", Aliases: []string{"mongo"}, Qualifiers: []string{"topic:mongodb"}},
+	{Name: "redis", Qualifiers: []string{"topic:redis"}},
+	{Name: "message queues", Qualifiers: []string{"topic:message-queue", "topic:rabbitmq", "topic:kafka"}},
+	{Name: "event-driven", Qualifiers: []string{"topic:event-driven"}},
+	{Name: "node.js", Aliases: []string{"nodejs", "node"}, Qualifiers: []string{"language:javascript topic:nodejs", "language:typescript topic:nodejs"}},
+	{Name: "react", Aliases: []string{"react.js", "reactjs"}, Qualifiers: []string{"topic:react"}},
+	{Name: "next.js", Aliases: []string{"nextjs"}, Qualifiers: []string{"topic:nextjs"}},
+	{Name: "machine learning", Aliases: []string{"ml"}, Qualifiers: []string{"topic:machine-learning", "topic:deep-learning"}},
+	{Name: "kubernetes", Aliases: []string{"k8s"}, Qualifiers: []string{"topic:kubernetes"}},
+	{Name: "docker", Qualifiers: []string{"topic:docker"}},
+}
+
+// Default returns the built-in mappings
+func Default() *Map {
+	m := &Map{
+		mappings: make(map[string]Mapping),
+		sources:  make(map[string]string),
+		aliases:  make(map[string]string),
+	}
+
+	for _, mapping := range builtinMappings {
+		m.add(mapping, SourceBuiltin)
+	}
+
+	return m
+}
+
+// Load returns the built-in mappings with the user file at path merged on
+// top. A missing file is not an error. The file has the form:
+//
+//	skills:
+//	  - name: Next.js
+//	    aliases: [nextjs]
+//	    qualifiers: ["topic:nextjs", "topic:react"]
+//
+// A user entry whose name or alias matches a built-in skill replaces its
+// qualifiers and adds its aliases.
+func Load(path string) (*Map, error) {
+	m := Default()
+
+	if path == "" {
+		return m, nil
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return m, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading skills file %s: %w", path, err)
+	}
+
+	var file struct {
+		Skills []Mapping `mapstructure:"skills"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("error parsing skills file %s: %w", path, err)
+	}
+
+	for i, mapping := range file.Skills {
+		if strings.TrimSpace(mapping.Name) == "" {
+			return nil, fmt.Errorf("skills file %s: entry %d has no name", path, i+1)
+		}
+		if len(mapping.Qualifiers) == 0 {
+			return nil, fmt.Errorf("skills file %s: %q has no qualifiers", path, mapping.Name)
+		}
+
+		m.add(mapping, SourceUser)
+	}
+
+	return m, nil
+}
+
+func (m *Map) add(mapping Mapping, source string) {
+	name := normalize(mapping.Name)
+
+	// Merge into an existing skill when the name is a known alias
+	if canonical, ok := m.aliases[name]; ok {
+		name = canonical
+	}
+
+	if existing, ok := m.mappings[name]; ok {
+		mapping.Aliases = append(existing.Aliases, mapping.Aliases...)
+	}
+
+	mapping.Name = name
+	m.mappings[name] = mapping
+	m.sources[name] = source
+
+	for _, alias := range mapping.Aliases {
+		alias = normalize(alias)
+		if alias != name {
+			m.aliases[alias] = name
+		}
+	}
+}
+
+// Resolve returns the qualifiers for a skill, falling back to a topic
+// qualifier derived from the skill name
+func (m *Map) Resolve(skill string) Resolution {
+	name := normalize(skill)
+	if canonical, ok := m.aliases[name]; ok {
+		name = canonical
+	}
+
+	if mapping, ok := m.mappings[name]; ok {
+		return Resolution{
+			Skill:      skill,
+			Canonical:  name,
+			Qualifiers: mapping.Qualifiers,
+			Source:     m.sources[name],
+		}
+	}
+
+	return Resolution{
+		Skill:      skill,
+		Canonical:  name,
+		Qualifiers: []string{"topic:" + Slug(skill)},
+		Source:     SourceFallback,
+	}
+}
+
+// All returns every mapping sorted by name along with its source
+func (m *Map) All() []Resolution {
+	names := make([]string, 0, len(m.mappings))
+	for name := range m.mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	all := []Resolution{}
+	for _, name := range names {
+		all = append(all, m.Resolve(name))
+	}
+
+	return all
+}
+
+// Aliases returns the aliases registered for a canonical skill name
+func (m *Map) Aliases(canonical string) []string {
+	return m.mappings[canonical].Aliases
+}
+
+var slugInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Slug converts a skill name into GitHub topic form, e.g. "Machine Learning"
+// becomes "machine-learning" and "Next.js" becomes "nextjs"
+func Slug(skill string) string {
+	slug := normalize(skill)
+	slug = strings.NewReplacer("+", "plus", "#", "sharp", ".", "").Replace(slug)
+	slug = strings.Join(strings.FieldsFunc(slug, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '/'
+	}), "-")
+	slug = slugInvalid.ReplaceAllString(slug, "")
+
+	return strings.Trim(slug, "-")
+}
+
+func normalize(skill string) string {
+	return strings.Join(strings.Fields(strings.ToLower(skill)), " ")
+}
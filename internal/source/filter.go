@@ -0,0 +1,77 @@
+package source
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// ValidateRepoFilters checks that the repository and organization patterns
+// are valid globs
+func ValidateRepoFilters(cfg types.FiltersConfig) error {
+	lists := map[string][]string{
+		"include_repos": cfg.IncludeRepos,
+		"exclude_repos": cfg.ExcludeRepos,
+		"include_orgs":  cfg.IncludeOrgs,
+		"exclude_orgs":  cfg.ExcludeOrgs,
+	}
+
+	for name, patterns := range lists {
+		for _, pattern := range patterns {
+			if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+				return fmt.Errorf("filters.%s: invalid pattern %q", name, pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AllowedRepo applies the allow and deny lists to a repository path such as
+// "owner/name". The organization is the first path segment, which is the
+// top-level group of a GitLab project like "group/subgroup/name".
+func AllowedRepo(cfg types.FiltersConfig, fullName string) bool {
+	fullName = strings.ToLower(fullName)
+	owner, _, _ := strings.Cut(fullName, "/")
+
+	if matchesAny(cfg.ExcludeRepos, fullName) || matchesAny(cfg.ExcludeOrgs, owner) {
+		return false
+	}
+
+	if len(cfg.IncludeRepos) == 0 && len(cfg.IncludeOrgs) == 0 {
+		return true
+	}
+
+	return matchesAny(cfg.IncludeRepos, fullName) || matchesAny(cfg.IncludeOrgs, owner)
+}
+
+// ExcludeRepos drops the issues whose repository the allow and deny lists
+// of cfg reject, counting them in Excluded. It applies to every source, so
+// sources only need to narrow their queries where the forge supports it.
+func (r *Result) ExcludeRepos(cfg types.FiltersConfig) {
+	kept := []types.CandidateIssue{}
+	for _, issue := range r.Issues {
+		if !AllowedRepo(cfg, issue.Repo) {
+			r.Excluded++
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	r.Issues = kept
+}
+
+// IsGlob reports whether pattern uses glob syntax
+func IsGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+func TestExcludeRepos(t *testing.T) {
+	cfg := types.FiltersConfig{
+		IncludeOrgs:  []string{"kept-*"},
+		ExcludeRepos: []string{"kept-org/archived"},
+	}
+	result := Result{Issues: []types.CandidateIssue{
+		{Repo: "kept-org/tool"},
+		{Repo: "Kept-Group/sub/project"},
+		{Repo: "kept-org/archived"},
+		{Repo: "other/tool"},
+	}}
+
+	result.ExcludeRepos(cfg)
+
+	if len(result.Issues) != 2 || result.Excluded != 2 {
+		t.Fatalf("ExcludeRepos() kept %v, excluded %d; want 2 kept and 2 excluded", result.Issues, result.Excluded)
+	}
+	if result.Issues[1].Repo != "Kept-Group/sub/project" {
+		t.Errorf("ExcludeRepos() dropped the GitLab subgroup project")
+	}
+}
+
+func TestValidateRepoFilters(t *testing.T) {
+	if err := ValidateRepoFilters(types.FiltersConfig{ExcludeOrgs: []string{"[bad"}}); err == nil {
+		t.Error("ValidateRepoFilters() accepted an invalid pattern")
+	}
+}
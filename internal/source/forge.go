@@ -0,0 +1,176 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// Default retry policy of an APIClient
+const (
+	DefaultMaxRetries  = 3
+	DefaultBaseBackoff = 10 * time.Second
+)
+
+// AuthError is returned when a forge rejects the token (401)
+type AuthError struct {
+	Forge string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%s authentication failed (401 Unauthorized): token may be invalid or expired", e.Forge)
+}
+
+// HTTPError is returned for any other non-200 response
+type HTTPError struct {
+	Forge      string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s API error %d: %s", e.Forge, e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// DecodeError is returned when a response body cannot be parsed
+type DecodeError struct {
+	Forge string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error parsing %s response: %v", e.Forge, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// APIClient sends the GET requests of a forge's REST API. Forge names the
+// forge in errors and logs, and Header holds the credentials sent with each
+// request. Zero retry settings use the defaults.
+type APIClient struct {
+	Forge       string
+	HTTPClient  *http.Client
+	Header      http.Header
+	MaxRetries  int
+	BaseBackoff time.Duration
+}
+
+// GetJSON performs a GET request and decodes the response into v, retrying
+// with backoff when the forge answers 429 Too Many Requests. It returns the
+// response headers so callers can follow pagination.
+func (a *APIClient) GetJSON(ctx context.Context, requestURL string, v any) (http.Header, error) {
+	maxRetries := a.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	backoff := a.BaseBackoff
+	if backoff <= 0 {
+		backoff = DefaultBaseBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		req.Header.Set("Accept", "application/json")
+		for name, values := range a.Header {
+			req.Header[name] = values
+		}
+
+		resp, err := a.HTTPClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error executing request: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			resp.Body.Close()
+
+			delay := backoff << attempt
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				delay = time.Duration(seconds) * time.Second
+			}
+
+			log.Printf("%s rate limit hit, retrying in %s", a.Forge, delay)
+			select {
+			case <-time.After(delay):
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		defer resp.Body.Close()
+
+		if err := a.checkResponse(resp); err != nil {
+			return nil, err
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return nil, &DecodeError{Forge: a.Forge, Err: err}
+		}
+
+		return resp.Header, nil
+	}
+}
+
+// checkResponse converts an unsuccessful response into a typed error
+func (a *APIClient) checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return &AuthError{Forge: a.Forge}
+	}
+
+	return &HTTPError{Forge: a.Forge, StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+// CollectIssues runs queries one after another and merges their issues,
+// dropping duplicates by URL. A failed query is recorded in the returned
+// *FetchError and the remaining queries still run, unless the token was
+// rejected or ctx is done.
+func CollectIssues[Q any](ctx context.Context, sourceName string, queries []Q, describe func(Q) string, run func(context.Context, Q) ([]types.CandidateIssue, error)) (Result, error) {
+	result := Result{Queries: len(queries)}
+	seenIssueURLs := make(map[string]bool)
+	fetchErr := &FetchError{Source: sourceName}
+
+	for _, q := range queries {
+		issues, err := run(ctx, q)
+		if err != nil {
+			fetchErr.Errors = append(fetchErr.Errors, &QueryError{Query: describe(q), Err: err})
+			var authErr *AuthError
+			if errors.As(err, &authErr) || ctx.Err() != nil {
+				break
+			}
+		}
+
+		for _, candidate := range issues {
+			if seenIssueURLs[candidate.URL] {
+				continue
+			}
+			seenIssueURLs[candidate.URL] = true
+			result.Issues = append(result.Issues, candidate)
+		}
+	}
+
+	if len(fetchErr.Errors) > 0 {
+		return result, fetchErr
+	}
+	return result, nil
+}
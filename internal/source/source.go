@@ -0,0 +1,89 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ashishra0/issue-finder/pkg/types"
+)
+
+// IssueSource finds candidate issues on one forge
+type IssueSource interface {
+	// Name identifies the source in state keys and output, e.g. "github"
+	// or "gitlab.com"
+	Name() string
+	// FetchRelevantIssues searches for issues matching the profile. Failed
+	// queries are reported in a *FetchError while the issues from the other
+	// queries are still returned.
+	FetchRelevantIssues(ctx context.Context, profile types.UserProfile) (Result, error)
+}
+
+// ClaimDetector is implemented by sources that can tell whether someone has
+// already taken an issue. It returns the issues to keep, how many looked
+// claimed and a *FetchError for issues that could not be checked.
+type ClaimDetector interface {
+	DetectClaims(ctx context.Context, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error)
+}
+
+// RepoHealthChecker is implemented by sources that can drop issues from
+// archived, inactive or small repositories. Looking up repositories costs
+// requests, so it runs on new issues only. It returns the issues to keep, how
+// many were dropped and a *FetchError for repositories that could not be
+// looked up; their issues are kept.
+type RepoHealthChecker interface {
+	FilterRepositories(ctx context.Context, issues []types.CandidateIssue) ([]types.CandidateIssue, int, error)
+}
+
+// Result holds the issues found by FetchRelevantIssues along with details
+// about how complete the search was
+type Result struct {
+	Issues []types.CandidateIssue
+	// Queries is the number of search queries executed
+	Queries int
+	// Incomplete lists queries for which the forge reported partial results
+	Incomplete []string
+	// Excluded is the number of issues dropped by the repository and
+	// organization allow and deny lists
+	Excluded int
+}
+
+// QueryError ties an error to the query or request that produced it
+type QueryError struct {
+	Query string
+	Err   error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query %q: %v", e.Query, e.Err)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// FetchError collects the queries that failed during a fetch. Results from
+// the queries that succeeded are still returned alongside it.
+type FetchError struct {
+	Source string
+	Errors []*QueryError
+}
+
+func (e *FetchError) Error() string {
+	prefix := ""
+	if e.Source != "" {
+		prefix = e.Source + ": "
+	}
+
+	if len(e.Errors) == 1 {
+		return prefix + e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s%d queries failed, first error: %v", prefix, len(e.Errors), e.Errors[0])
+}
+
+func (e *FetchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
@@ -0,0 +1,37 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseWindow turns a relative window such as "90d", "8w", "6m" or "1y" into
+// the date that far before now. An empty window returns the zero time.
+func ParseWindow(window string, now time.Time) (time.Time, error) {
+	window = strings.TrimSpace(strings.ToLower(window))
+	if window == "" {
+		return time.Time{}, nil
+	}
+
+	invalid := fmt.Errorf("invalid window %q (expected a number followed by d, w, m or y, e.g. 90d)", window)
+
+	n, err := strconv.Atoi(window[:len(window)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, invalid
+	}
+
+	switch window[len(window)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, invalid
+	}
+}
@@ -69,22 +69,31 @@ func (m *Manager) Save(state types.State) error {
 	return nil
 }
 
-// FilterNewIssues filters out already processed issues and marks new ones
-func (m *Manager) FilterNewIssues(state *types.State, issues []map[string]any) []map[string]any {
-	newIssues := []map[string]any{}
+// FilterNewIssues filters out already processed issues and duplicates. The
+// new issues are not marked yet; see MarkProcessed.
+func (m *Manager) FilterNewIssues(state *types.State, issues []types.CandidateIssue) []types.CandidateIssue {
+	newIssues := []types.CandidateIssue{}
+	seen := make(map[string]bool)
 
 	for _, issue := range issues {
-		issueKey := fmt.Sprintf("%s/%d", issue["repo"], issue["number"])
+		issueKey := issue.Key()
 
-		if !state.ProcessedIssues[issueKey] {
+		if !state.ProcessedIssues[issueKey] && !seen[issueKey] {
 			newIssues = append(newIssues, issue)
-			state.ProcessedIssues[issueKey] = true
+			seen[issueKey] = true
 		}
 	}
 
 	return newIssues
 }
 
+// MarkProcessed records issues as processed so later runs skip them
+func (m *Manager) MarkProcessed(state *types.State, issues []types.CandidateIssue) {
+	for _, issue := range issues {
+		state.ProcessedIssues[issue.Key()] = true
+	}
+}
+
 // AddMatches adds new matches to state and maintains history limit
 func (m *Manager) AddMatches(state *types.State, matches []types.IssueMatch, maxMatches int) {
 	state.AllMatches = append(matches, state.AllMatches...)
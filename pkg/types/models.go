@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // UserProfile represents a developer's profile used for matching
 type UserProfile struct {
@@ -10,8 +13,9 @@ type UserProfile struct {
 	ExperienceYears int      `json:"experience_years" yaml:"experience_years"`
 }
 
-// IssueMatch represents a GitHub issue that matches the user's profile
+// IssueMatch represents an issue that matches the user's profile
 type IssueMatch struct {
+	Source      string   `json:"source,omitempty"`
 	Repo        string   `json:"repo"`
 	IssueNumber int      `json:"issue_number"`
 	Title       string   `json:"title"`
@@ -30,15 +34,112 @@ type State struct {
 	AllMatches      []IssueMatch    `json:"all_matches"`
 }
 
+// CandidateIssue is an open issue found by a search that has not been
+// evaluated yet
+type CandidateIssue struct {
+	// Source names the forge the issue was found on; see DefaultSource
+	Source            string        `json:"source,omitempty"`
+	Repo              string        `json:"repo"`
+	Number            int           `json:"number"`
+	Title             string        `json:"title"`
+	URL               string        `json:"url"`
+	Labels            []string      `json:"labels"`
+	Body              string        `json:"body"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	Comments          int           `json:"comments"`
+	AuthorAssociation string        `json:"author_association,omitempty"`
+	Repository        *RepoMetadata `json:"repository,omitempty"`
+	// RecentComments and LinkedPullRequests are only filled by backends
+	// that fetch them, which set ActivityLoaded
+	RecentComments     []IssueComment      `json:"recent_comments,omitempty"`
+	LinkedPullRequests []LinkedPullRequest `json:"linked_pull_requests,omitempty"`
+	ActivityLoaded     bool                `json:"-"`
+	// Claimed explains why the issue looks taken by someone else, when
+	// claimed issues are flagged rather than dropped
+	Claimed string `json:"claimed,omitempty"`
+}
+
+// DefaultSource is the source name of github.com. Its issues keep the
+// unprefixed state keys used before other forges were supported.
+const DefaultSource = "github"
+
+// Key identifies the issue in State.ProcessedIssues
+func (c CandidateIssue) Key() string {
+	if c.Source == "" || c.Source == DefaultSource {
+		return fmt.Sprintf("%s/%d", c.Repo, c.Number)
+	}
+	return fmt.Sprintf("%s:%s/%d", c.Source, c.Repo, c.Number)
+}
+
+// IssueComment is a comment on a candidate issue
+type IssueComment struct {
+	Author string `json:"author"`
+	// AuthorAssociation is the author's role in the repository, e.g. OWNER,
+	// MEMBER, COLLABORATOR or CONTRIBUTOR
+	AuthorAssociation string    `json:"author_association,omitempty"`
+	Body              string    `json:"body"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// LinkedPullRequest is a pull request that references a candidate issue
+type LinkedPullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	// State is "open", "closed" or "merged"
+	State string `json:"state"`
+}
+
+// RepoMetadata describes the repository an issue belongs to. It is nil on a
+// CandidateIssue until repository details have been fetched.
+type RepoMetadata struct {
+	FullName        string    `json:"full_name"`
+	Stars           int       `json:"stars"`
+	Forks           int       `json:"forks"`
+	OpenIssues      int       `json:"open_issues"`
+	PushedAt        time.Time `json:"pushed_at"`
+	Archived        bool      `json:"archived"`
+	Disabled        bool      `json:"disabled"`
+	License         string    `json:"license,omitempty"`
+	DefaultBranch   string    `json:"default_branch"`
+	PrimaryLanguage string    `json:"language,omitempty"`
+}
+
 // GitHubIssue represents a GitHub issue from the API
 type GitHubIssue struct {
-	Number    int       `json:"number"`
-	Title     string    `json:"title"`
-	URL       string    `json:"html_url"`
-	Labels    []Label   `json:"labels"`
-	CreatedAt time.Time `json:"created_at"`
-	Body      string    `json:"body"`
-	RepoURL   string    `json:"repository_url"`
+	Number            int       `json:"number"`
+	Title             string    `json:"title"`
+	URL               string    `json:"html_url"`
+	Labels            []Label   `json:"labels"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	Body              string    `json:"body"`
+	RepoURL           string    `json:"repository_url"`
+	Comments          int       `json:"comments"`
+	AuthorAssociation string    `json:"author_association"`
+	// PullRequest is set when a repository issue listing returns a pull request
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+	Reactions struct {
+		TotalCount int `json:"total_count"`
+	} `json:"reactions"`
+}
+
+// GitHubRepository represents a repository from the GitHub API
+type GitHubRepository struct {
+	FullName        string    `json:"full_name"`
+	StargazersCount int       `json:"stargazers_count"`
+	ForksCount      int       `json:"forks_count"`
+	OpenIssuesCount int       `json:"open_issues_count"`
+	PushedAt        time.Time `json:"pushed_at"`
+	Archived        bool      `json:"archived"`
+	Disabled        bool      `json:"disabled"`
+	DefaultBranch   string    `json:"default_branch"`
+	Language        string    `json:"language"`
+	License         *struct {
+		SPDXID string `json:"spdx_id"`
+	} `json:"license"`
 }
 
 // Label represents a GitHub label
@@ -51,6 +152,22 @@ type Config struct {
 	Profile     UserProfile       `yaml:"profile"`
 	Preferences PreferencesConfig `yaml:"preferences"`
 	API         APIConfig         `yaml:"api"`
+	AI          AIConfig          `yaml:"ai"`
+	Search      SearchConfig      `yaml:"search"`
+	Filters     FiltersConfig     `yaml:"filters"`
+}
+
+// ForgeConfig configures an additional issue source such as a GitLab or
+// Gitea instance
+type ForgeConfig struct {
+	// Name identifies the source in state keys and output; defaults to
+	// the host of BaseURL
+	Name     string `yaml:"name" mapstructure:"name"`
+	BaseURL  string `yaml:"base_url" mapstructure:"base_url"`
+	TokenEnv string `yaml:"token_env" mapstructure:"token_env"`
+	// ProjectsPerQuery limits how many matching projects are scanned for
+	// issues per skill and label
+	ProjectsPerQuery int `yaml:"projects_per_query" mapstructure:"projects_per_query"`
 }
 
 // PreferencesConfig represents user preferences
@@ -65,4 +182,119 @@ type PreferencesConfig struct {
 type APIConfig struct {
 	AnthropicKeyEnv string `yaml:"anthropic_key_env"`
 	GitHubTokenEnv  string `yaml:"github_token_env"`
+	GitHubBaseURL   string `yaml:"github_base_url"`
+	GitHubBackend   string `yaml:"github_backend"`
+	// GitHubApp authenticates as a GitHub App installation instead of with
+	// a personal token when AppID is set
+	GitHubApp GitHubAppConfig `yaml:"github_app"`
+}
+
+// GitHubAppConfig identifies a GitHub App installation and its private key,
+// read from PrivateKeyPath or the PrivateKeyEnv environment variable
+type GitHubAppConfig struct {
+	AppID          string `yaml:"app_id" mapstructure:"app_id"`
+	InstallationID int64  `yaml:"installation_id" mapstructure:"installation_id"`
+	PrivateKeyPath string `yaml:"private_key_path" mapstructure:"private_key_path"`
+	PrivateKeyEnv  string `yaml:"private_key_env" mapstructure:"private_key_env"`
+}
+
+// AIConfig selects the LLM provider used to evaluate issues
+type AIConfig struct {
+	// Provider is "anthropic" (default), "openai" for any
+	// OpenAI-compatible chat completions API, or "ollama" for a local model
+	Provider string `yaml:"provider" mapstructure:"provider"`
+	// Model overrides the provider's default model
+	Model     string `yaml:"model" mapstructure:"model"`
+	MaxTokens int    `yaml:"max_tokens" mapstructure:"max_tokens"`
+	// Temperature is nil to use the provider's default
+	Temperature *float64 `yaml:"temperature" mapstructure:"temperature"`
+	// Timeout bounds an evaluation request, e.g. "10m"
+	Timeout string `yaml:"timeout" mapstructure:"timeout"`
+	// MaxMatches is the number of best matches kept per search
+	MaxMatches int `yaml:"max_matches" mapstructure:"max_matches"`
+	// BatchTokens is the estimated prompt size of one batch of issues;
+	// larger candidate sets are evaluated in batches and then ranked
+	BatchTokens      int          `yaml:"batch_tokens" mapstructure:"batch_tokens"`
+	BatchConcurrency int          `yaml:"batch_concurrency" mapstructure:"batch_concurrency"`
+	OpenAI           OpenAIConfig `yaml:"openai" mapstructure:"openai"`
+	Ollama           OllamaConfig `yaml:"ollama" mapstructure:"ollama"`
+}
+
+// OpenAIConfig configures an OpenAI-compatible chat completions API
+type OpenAIConfig struct {
+	BaseURL   string `yaml:"base_url" mapstructure:"base_url"`
+	Model     string `yaml:"model" mapstructure:"model"`
+	APIKeyEnv string `yaml:"api_key_env" mapstructure:"api_key_env"`
+}
+
+// OllamaConfig configures a local Ollama or llama.cpp server
+type OllamaConfig struct {
+	BaseURL string `yaml:"base_url" mapstructure:"base_url"`
+	Model   string `yaml:"model" mapstructure:"model"`
+	// API is "ollama" (default) for Ollama's native API, or "openai" for
+	// the OpenAI-compatible API of llama.cpp's server
+	API string `yaml:"api" mapstructure:"api"`
+}
+
+// SearchConfig controls how GitHub search queries are built and executed
+type SearchConfig struct {
+	MaxPages           int `yaml:"max_pages" mapstructure:"max_pages"`
+	MaxResultsPerQuery int `yaml:"max_results_per_query" mapstructure:"max_results_per_query"`
+
+	// CreatedWithin and UpdatedWithin are relative windows such as "90d",
+	// "8w", "6m" or "1y"; empty means no limit
+	CreatedWithin string `yaml:"created_within" mapstructure:"created_within"`
+	UpdatedWithin string `yaml:"updated_within" mapstructure:"updated_within"`
+	// Labels produces one query per label per skill; empty means no label filter
+	Labels []string `yaml:"labels" mapstructure:"labels"`
+	// MinComments, MaxComments and MinReactions are ignored when 0
+	MinComments     int  `yaml:"min_comments" mapstructure:"min_comments"`
+	MaxComments     int  `yaml:"max_comments" mapstructure:"max_comments"`
+	MinReactions    int  `yaml:"min_reactions" mapstructure:"min_reactions"`
+	IncludeAssigned bool `yaml:"include_assigned" mapstructure:"include_assigned"`
+	// Sort orders search results: "created", "updated", "comments",
+	// "reactions", "interactions", or "mixed" to run each query once per
+	// ordering in MixedSorts and interleave the results
+	Sort       string   `yaml:"sort" mapstructure:"sort"`
+	MixedSorts []string `yaml:"mixed_sorts" mapstructure:"mixed_sorts"`
+	// BodyMaxChars is the character budget for each issue body sent for
+	// evaluation; 0 uses the default
+	BodyMaxChars int `yaml:"body_max_chars" mapstructure:"body_max_chars"`
+
+	// Skills holds per-skill overrides keyed by lower-cased skill name
+	Skills map[string]SkillSearchConfig `yaml:"skills" mapstructure:"skills"`
+}
+
+// SkillSearchConfig overrides SearchConfig for a single skill. Nil and empty
+// fields fall back to the global value.
+type SkillSearchConfig struct {
+	CreatedWithin string   `yaml:"created_within" mapstructure:"created_within"`
+	UpdatedWithin string   `yaml:"updated_within" mapstructure:"updated_within"`
+	Labels        []string `yaml:"labels" mapstructure:"labels"`
+	MinComments   *int     `yaml:"min_comments" mapstructure:"min_comments"`
+	MaxComments   *int     `yaml:"max_comments" mapstructure:"max_comments"`
+	MinReactions  *int     `yaml:"min_reactions" mapstructure:"min_reactions"`
+	Sort          string   `yaml:"sort" mapstructure:"sort"`
+}
+
+// FiltersConfig controls which search results are dropped before evaluation
+type FiltersConfig struct {
+	// MinStars drops repositories with fewer stars (0 disables the check)
+	MinStars int `yaml:"min_stars" mapstructure:"min_stars"`
+	// MaxInactiveDays drops repositories without a push in this many days
+	// (0 disables the check)
+	MaxInactiveDays int `yaml:"max_inactive_days" mapstructure:"max_inactive_days"`
+	// IncludeArchived keeps issues from archived repositories
+	IncludeArchived bool `yaml:"include_archived" mapstructure:"include_archived"`
+	// Claimed is "drop", "flag" or "keep" for issues someone has already
+	// claimed in a comment or with an open pull request
+	Claimed string `yaml:"claimed" mapstructure:"claimed"`
+
+	// Repository allow and deny lists. Entries may use glob patterns such as
+	// "my-employer/*" or "*/awesome-*". When an include list is set, only
+	// matching repositories are searched.
+	IncludeRepos []string `yaml:"include_repos" mapstructure:"include_repos"`
+	ExcludeRepos []string `yaml:"exclude_repos" mapstructure:"exclude_repos"`
+	IncludeOrgs  []string `yaml:"include_orgs" mapstructure:"include_orgs"`
+	ExcludeOrgs  []string `yaml:"exclude_orgs" mapstructure:"exclude_orgs"`
 }